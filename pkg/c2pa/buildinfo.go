@@ -0,0 +1,108 @@
+package c2pa
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	rustC2PA "git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/c2pa"
+)
+
+// BuildInfo describes the c2pa-rs core and Go binding a program is linked
+// against.
+type BuildInfo struct {
+	// SDKVersion is rustC2PA.SdkVersion()'s raw string.
+	SDKVersion string
+	// CoreVersion is rustC2PA.Version()'s raw string, the c2pa-rs crate
+	// version embedded in a signed manifest's claim_generator.
+	CoreVersion string
+	// Major/Minor/Patch are parsed from CoreVersion if it's valid
+	// "major.minor.patch"-style semver; all zero if it isn't.
+	Major, Minor, Patch int
+	// GoModuleVersion and GoModuleSum identify the version of this Go
+	// module's own source (not the Rust core) that the running binary was
+	// built from, via runtime/debug.ReadBuildInfo. Empty if the binary
+	// wasn't built with module information (e.g. `go run`).
+	GoModuleVersion string
+	GoModuleSum     string
+	// VCSRevision and VCSDirty come from the same build info, identifying
+	// the exact commit (and whether the tree had local modifications) the
+	// running binary was built from.
+	VCSRevision string
+	VCSDirty    bool
+}
+
+// GetBuildInfo reports the c2pa-rs/SDK versions alongside what Go's own
+// build metadata knows about this module. The c2pa-rs side is limited to
+// whatever SdkVersion/Version already expose as bare strings: the uniffi
+// bindings don't have an FFI function returning a structured build record
+// (git commit, target triple, enabled feature flags), and adding one means
+// changing the Rust core and regenerating the bindings, both out of reach
+// from this Go module alone.
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		SDKVersion:  rustC2PA.SdkVersion(),
+		CoreVersion: rustC2PA.Version(),
+	}
+	info.Major, info.Minor, info.Patch, _ = parseSemver(info.CoreVersion)
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoModuleVersion = bi.Main.Version
+		info.GoModuleSum = bi.Main.Sum
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.VCSRevision = setting.Value
+			case "vcs.modified":
+				info.VCSDirty = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// RequireVersion returns an error if the linked c2pa-rs core's version is
+// older than min, a "major.minor.patch" string.
+func RequireVersion(min string) error {
+	info := GetBuildInfo()
+	wantMajor, wantMinor, wantPatch, err := parseSemver(min)
+	if err != nil {
+		return fmt.Errorf("c2pa: RequireVersion: %w", err)
+	}
+	have := [3]int{info.Major, info.Minor, info.Patch}
+	want := [3]int{wantMajor, wantMinor, wantPatch}
+	for i := range have {
+		if have[i] != want[i] {
+			if have[i] > want[i] {
+				return nil
+			}
+			return fmt.Errorf("c2pa: core version %s is older than required %s", info.CoreVersion, min)
+		}
+	}
+	return nil
+}
+
+// parseSemver parses a "major.minor.patch" version, tolerating a leading
+// "v" and a pre-release/build suffix on the patch component (e.g.
+// "1.2.3-beta.1" or "1.2.3+build5").
+func parseSemver(v string) (major, minor, patch int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("not a major.minor.patch version: %q", v)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		for _, sep := range []string{"-", "+"} {
+			if idx := strings.Index(p, sep); idx >= 0 {
+				p = p[:idx]
+			}
+		}
+		n, convErr := strconv.Atoi(p)
+		if convErr != nil {
+			return 0, 0, 0, fmt.Errorf("not a major.minor.patch version: %q", v)
+		}
+		nums[i] = n
+	}
+	return nums[0], nums[1], nums[2], nil
+}
@@ -1,57 +1,253 @@
 package c2pa
 
 import (
+	"context"
 	"crypto"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 
 	rustC2PA "git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/c2pa"
 	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/manifestdefinition"
 	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/manifeststore"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/manifestfetch"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/telemetry"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/timestamp"
 )
 
 // #cgo LDFLAGS: -L../../target/release -lc2pa -lm
 // #cgo darwin LDFLAGS: -framework Security
 import "C"
 
+// defaultMu guards defaultTelemetry and defaultLogger, the package-wide
+// fallbacks set via SetTracerProvider/SetLogger. Most long-running
+// processes (signing farms, verification services) configure these once at
+// startup and never touch per-call options, so every Sign/FromStream call
+// getting the same tracer/logger without threading an option through every
+// call site is the common case; per-call ReadOption/BuilderParams.Telemetry
+// still take priority when set.
+var (
+	defaultMu        sync.RWMutex
+	defaultTelemetry *telemetry.Hooks
+	defaultLogger    *slog.Logger
+)
+
+// SetTracerProvider installs h as the package-wide default telemetry hooks
+// for Sign/FromStream calls that don't set their own via WithReadTelemetry
+// or BuilderParams.Telemetry. Passing nil restores the default of no
+// telemetry.
+func SetTracerProvider(h *telemetry.Hooks) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultTelemetry = h
+}
+
+// SetLogger installs l as the package-wide default logger for Sign/
+// FromStream calls that don't set their own via WithReadLogger or
+// BuilderParams.Logger. Passing nil restores the default of slog.Default().
+func SetLogger(l *slog.Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+func defaultTelemetryHooks() *telemetry.Hooks {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultTelemetry
+}
+
+func defaultSlogLogger() *slog.Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultLogger != nil {
+		return defaultLogger
+	}
+	return slog.Default()
+}
+
 type Reader interface {
 	GetManifest(label string) *manifeststore.Manifest
 	GetActiveManifest() *manifeststore.Manifest
 	GetProvenanceCertChain() string
+	// GetValidationStatus returns the manifest store's own per-assertion
+	// validation status codes, the same ones FromStream/FromStreamContext
+	// summarize into a ValidationError, so callers can act on individual
+	// failures instead of re-parsing that error's message.
+	GetValidationStatus() []ValidationStatus
+	// GetAttestations decodes and DSSE-verifies any in-toto attestations
+	// embedded as assertions by C2PABuilder.AddAttestation.
+	GetAttestations() []Attestation
+	// GetRemoteIngredientManifest returns the raw bytes fetched for an
+	// ingredient manifest referenced by url, if WithManifestFetcher was
+	// configured and a fetch for that URL succeeded.
+	GetRemoteIngredientManifest(url string) ([]byte, bool)
+}
+
+// ReadOption configures telemetry/logging for FromStream and FromFile.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	telemetry       *telemetry.Hooks
+	logger          *slog.Logger
+	manifestFetcher manifestfetch.Fetcher
+}
+
+// WithReadTelemetry reports a tracing span and event counts for the
+// FromStream/FromFile call to h.
+func WithReadTelemetry(h *telemetry.Hooks) ReadOption {
+	return func(o *readOptions) { o.telemetry = h }
+}
+
+// WithReadLogger emits structured log events for the FromStream/FromFile
+// call to l instead of slog.Default().
+func WithReadLogger(l *slog.Logger) ReadOption {
+	return func(o *readOptions) { o.logger = l }
+}
+
+// WithManifestFetcher configures f to resolve any ingredient manifests
+// that the manifest store references by URL instead of embedding, the
+// results surfaced afterward via Reader.GetRemoteIngredientManifest. This
+// module's generated Rust bindings don't expose a fetch-callback hook the
+// core itself calls into, so unlike the rest of manifest parsing this
+// resolution happens at the Go API boundary: FromStream/FromFile scan the
+// already-parsed manifest store's own JSON for references and fetch each
+// one via f, rather than intercepting anything inside the Rust SDK.
+func WithManifestFetcher(f manifestfetch.Fetcher) ReadOption {
+	return func(o *readOptions) { o.manifestFetcher = f }
+}
+
+func FromStream(target io.ReadSeeker, mType string, opts ...ReadOption) (Reader, error) {
+	return FromStreamContext(context.Background(), target, mType, opts...)
 }
 
-func FromStream(target io.ReadSeeker, mType string) (Reader, error) {
-	stream := C2PAStreamReader{target}
+// FromStreamContext is FromStream, but every Stream callback checks ctx
+// first and fails immediately once it's canceled or its deadline passes.
+func FromStreamContext(ctx context.Context, target io.ReadSeeker, mType string, opts ...ReadOption) (Reader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var ro readOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+	log := ro.logger
+	if log == nil {
+		log = defaultSlogLogger()
+	}
+	log = log.With("mimeType", mType)
+	hooks := ro.telemetry
+	if hooks == nil {
+		hooks = defaultTelemetryHooks()
+	}
+	ctx, span := hooks.StartSpanPropagating(ctx, "c2pa.FromStream")
+	var err error
+	defer func() { span.End(err) }()
+
+	stream := NewC2PAStreamReaderContext(ctx, target)
 	r := rustC2PA.NewReader()
-	r.FromStream(mType, &stream)
+	r.FromStream(mType, stream)
 	ret, err := r.Json()
 	if err != nil {
+		log.Error("c2pa: FromStream failed", "error", err)
 		return nil, err
 	}
 	certs, err := r.GetProvenanceCertChain()
 	if err != nil {
+		log.Error("c2pa: FromStream failed", "error", err)
 		return nil, err
 	}
 	var store manifeststore.ManifestStoreSchemaJson
 	err = json.Unmarshal([]byte(ret), &store)
 	if err != nil {
+		log.Error("c2pa: FromStream failed", "error", err)
 		return nil, err
 	}
+	remoteManifests := resolveRemoteIngredientManifests(ctx, ret, ro.manifestFetcher, log)
 	if len(store.ValidationStatus) > 0 {
-		errBs, err := json.Marshal(store.ValidationStatus)
-		if err != nil {
+		statuses, convErr := convertValidationStatus(store.ValidationStatus)
+		if convErr != nil {
+			err = convErr
 			return nil, err
 		}
-		return &C2PAReader{store: &store, certs: certs}, fmt.Errorf("validation error: %s", string(errBs))
+		err = &ValidationError{Statuses: statuses}
+		log.Warn("c2pa: FromStream validation status non-empty", "error", err)
+		return &C2PAReader{store: &store, certs: certs, remoteManifests: remoteManifests}, err
 	}
-	return &C2PAReader{store: &store, certs: certs}, nil
+	log.Debug("c2pa: FromStream succeeded")
+	return &C2PAReader{store: &store, certs: certs, remoteManifests: remoteManifests}, nil
 }
 
-func FromFile(fname string) (Reader, error) {
+// resolveRemoteIngredientManifests scans raw, the manifest store's own
+// JSON, for ingredient entries that reference their manifest by URL
+// instead of embedding it, and fetches each via fetcher. It returns nil if
+// fetcher is nil or no such reference is found; fetch failures are logged
+// and otherwise ignored, since a remote ingredient manifest is supplementary
+// context rather than something FromStream itself depends on.
+func resolveRemoteIngredientManifests(ctx context.Context, raw string, fetcher manifestfetch.Fetcher, log *slog.Logger) map[string][]byte {
+	if fetcher == nil {
+		return nil
+	}
+	var decoded struct {
+		Manifests map[string]struct {
+			Ingredients []struct {
+				Manifest *struct {
+					URL string `json:"url"`
+				} `json:"manifest"`
+			} `json:"ingredients"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil
+	}
+	var urls []string
+	for _, m := range decoded.Manifests {
+		for _, ing := range m.Ingredients {
+			if ing.Manifest != nil && ing.Manifest.URL != "" {
+				urls = append(urls, ing.Manifest.URL)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	out := make(map[string][]byte, len(urls))
+	for _, url := range urls {
+		bs, err := fetcher.FetchManifest(ctx, url)
+		if err != nil {
+			log.Warn("c2pa: fetch remote ingredient manifest failed", "url", url, "error", err)
+			continue
+		}
+		out[url] = bs
+	}
+	return out
+}
+
+// FromHTTP reads and validates the manifest embedded in an HTTP response
+// body, using the response's Content-Type header as the asset's MIME type.
+// The body is spilled to a temp file rather than buffered in memory, since
+// C2PA parsing requires a seekable source and response bodies can be large;
+// the temp file is removed before FromHTTP returns.
+func FromHTTP(resp *http.Response, opts ...ReadOption) (Reader, error) {
+	mType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("c2pa: couldn't determine MIME type from response: %w", err)
+	}
+	stream, cleanup, err := NewC2PAStreamReaderSpilled(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return FromStream(stream, mType, opts...)
+}
+
+func FromFile(fname string, opts ...ReadOption) (Reader, error) {
 	mType := mime.TypeByExtension(filepath.Ext(fname))
 	if mType == "" {
 		return nil, fmt.Errorf("couldn't find MIME type for filename %s", fname)
@@ -61,12 +257,21 @@ func FromFile(fname string) (Reader, error) {
 		return nil, err
 	}
 	defer f.Close()
-	return FromStream(f, mType)
+	return FromStream(f, mType, opts...)
 }
 
 type C2PAReader struct {
 	store *manifeststore.ManifestStoreSchemaJson
 	certs string
+	// remoteManifests holds the result of WithManifestFetcher resolving
+	// any by-URL ingredient manifest references, keyed by URL.
+	remoteManifests map[string][]byte
+}
+
+// GetRemoteIngredientManifest implements Reader.
+func (r *C2PAReader) GetRemoteIngredientManifest(url string) ([]byte, bool) {
+	bs, ok := r.remoteManifests[url]
+	return bs, ok
 }
 
 func (r *C2PAReader) GetManifest(label string) *manifeststore.Manifest {
@@ -88,9 +293,89 @@ func (r *C2PAReader) GetProvenanceCertChain() string {
 	return r.certs
 }
 
+// GetValidationStatus implements Reader.
+func (r *C2PAReader) GetValidationStatus() []ValidationStatus {
+	statuses, err := convertValidationStatus(r.store.ValidationStatus)
+	if err != nil {
+		return nil
+	}
+	return statuses
+}
+
+// convertValidationStatus re-decodes raw (the manifest store's own
+// validation status entries, typed against the generated manifeststore
+// package) as this package's ValidationStatus, since the two have the same
+// JSON shape but aren't the same Go type.
+func convertValidationStatus(raw any) ([]ValidationStatus, error) {
+	bs, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []ValidationStatus
+	if err := json.Unmarshal(bs, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
 type Builder interface {
 	Sign(input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) error
 	SignFile(infile, outfile string) error
+	// SignContext is Sign, but every Stream callback checks ctx first and
+	// fails immediately once it's canceled or its deadline passes.
+	SignContext(ctx context.Context, input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) error
+	// SignMulti is Sign, but after output holds the signed asset its
+	// contents are copied to each of extraOutputs, so one signing pass can
+	// populate several destinations (e.g. local disk plus an upload
+	// stream) without re-running the signer. extraOutputs are plain
+	// io.Writer, not io.WriteSeeker, specifically so non-seekable
+	// destinations like an io.Pipe or a network stream can be used; that
+	// rules out multiplexing writes to them while output is being
+	// written, since the signer itself seeks output (e.g. to patch in the
+	// manifest after the asset hash is known), a sequence a non-seekable
+	// destination can't replay. Copying only after output holds the
+	// finished asset is correct for every destination type, at the cost
+	// of output needing to hold the whole signed asset before any copy
+	// starts. By default all of extraOutputs are attempted even if one
+	// fails, and every failure is aggregated into the returned
+	// *MultiOutputError; pass WithAbortOnFirstError to stop at the first
+	// failing output instead.
+	SignMulti(input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string, extraOutputs []io.Writer, opts ...SignMultiOption) error
+	// SignAsync starts signing in a separate goroutine and returns
+	// immediately with a SignFuture; call Wait on it to block for the
+	// result. ctx governs both the signing operation itself (via
+	// SignContext) and, separately, how long Wait is willing to block.
+	SignAsync(ctx context.Context, input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) *SignFuture
+	// AddIngredient registers ingredientJSON (an IngredientDefinition) with
+	// the manifest being built, reading its asset bytes from stream.
+	AddIngredient(ingredientJSON string, format string, stream io.ReadSeeker) error
+	// AddResource attaches stream's bytes to the manifest under uri, e.g.
+	// for a thumbnail or ingredient referenced by a manifest assertion.
+	AddResource(uri string, stream io.ReadSeeker) error
+	// FromArchive replaces this builder's state with the one serialized in
+	// archive, as previously written by ToArchive.
+	FromArchive(archive io.ReadSeeker) error
+	// ToArchive serializes this builder's current state to output, so it
+	// can be resumed later via FromArchive.
+	ToArchive(output io.ReadWriteSeeker) error
+}
+
+// SignFuture is the pending result of a SignAsync call.
+type SignFuture struct {
+	done chan struct{}
+	err  error
+}
+
+// Wait blocks until the signing operation completes or ctx is canceled,
+// whichever comes first. Calling Wait again after it returns is safe and
+// returns the same result immediately.
+func (f *SignFuture) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 type BuilderParams struct {
@@ -98,12 +383,66 @@ type BuilderParams struct {
 	Signer    crypto.Signer
 	TAURL     string
 	Algorithm *SigningAlgorithm
+	// TimestampProvider, if set, takes priority over TAURL for any
+	// Go-side timestamping this package does directly (currently, the
+	// detached envelopes built by the envelope subpackage). The
+	// manifest embedded by Sign/SignFile still has its RFC3161
+	// timestamp fetched by the underlying Rust SDK from TAURL, since
+	// that step happens inside the native signer and isn't yet
+	// reachable from Go; TimestampProvider exists so callers can share
+	// one pluggable TSA client (with fallback/caching/offline behavior)
+	// across both paths once embedded signing grows the same hook.
+	TimestampProvider timestamp.Provider
+	// Telemetry, if set, receives tracing spans and metrics for Sign/
+	// SignFile. A nil value (the default) falls back to the package-wide
+	// hooks set by SetTracerProvider; if none were set, no telemetry is
+	// reported.
+	Telemetry *telemetry.Hooks
+	// Logger receives a structured event for each Sign/SignFile call. A
+	// nil value (the default) falls back to the package-wide logger set by
+	// SetLogger, or to slog.Default() if none was set.
+	Logger *slog.Logger
+}
+
+// logger returns params.Logger, or the package-wide default (see
+// SetLogger) if unset.
+func (params *BuilderParams) logger() *slog.Logger {
+	if params.Logger != nil {
+		return params.Logger
+	}
+	return defaultSlogLogger()
+}
+
+// telemetryHooks returns params.Telemetry, or the package-wide default (see
+// SetTracerProvider) if unset.
+func (params *BuilderParams) telemetryHooks() *telemetry.Hooks {
+	if params.Telemetry != nil {
+		return params.Telemetry
+	}
+	return defaultTelemetryHooks()
+}
+
+// ResolveTimestampProvider returns params.TimestampProvider if set, or
+// otherwise an HTTP provider for params.TAURL if one is configured. It
+// returns nil if neither is set.
+func (params *BuilderParams) ResolveTimestampProvider() timestamp.Provider {
+	if params.TimestampProvider != nil {
+		return params.TimestampProvider
+	}
+	if params.TAURL != "" {
+		return timestamp.NewHTTPProvider(params.TAURL)
+	}
+	return nil
 }
 
 type C2PABuilder struct {
 	builder  *rustC2PA.Builder
 	manifest *ManifestDefinition
 	params   *BuilderParams
+	// pendingAttestations holds predicates queued by AddAttestation,
+	// embedded as assertions the next time Sign/SignContext runs (see
+	// embedAttestations in attestation.go).
+	pendingAttestations []pendingAttestation
 }
 
 type ManifestDefinition manifestdefinition.ManifestDefinitionSchemaJson
@@ -123,18 +462,154 @@ func NewBuilder(manifest *ManifestDefinition, params *BuilderParams) (Builder, e
 }
 
 func (b *C2PABuilder) Sign(input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) error {
+	return b.SignContext(context.Background(), input, output, mimeType)
+}
+
+// SignContext implements Builder.
+func (b *C2PABuilder) SignContext(ctx context.Context, input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(b.pendingAttestations) > 0 {
+		if err := b.embedAttestations(input); err != nil {
+			return err
+		}
+	}
+	hooks := b.params.telemetryHooks()
+	spanCtx, span := hooks.StartSpanPropagating(ctx, "c2pa.Builder.Sign")
+	log := b.params.logger().With("mimeType", mimeType, "algorithm", b.params.Algorithm.Name)
+	log.Debug("c2pa: signing started")
+	var err error
+	defer func() { span.End(err) }()
+
 	mySigner := &C2PACallbackSigner{
 		signer:    b.params.Signer,
 		algorithm: *b.params.Algorithm,
+		telemetry: hooks,
+		ctx:       ctx,
 	}
 	signer := rustC2PA.NewCallbackSigner(mySigner, b.params.Algorithm.RustC2PAType, b.params.Cert, &b.params.TAURL)
-	_, err := b.builder.Sign(mimeType, &C2PAStreamReader{input}, &C2PAStreamWriter{output}, signer)
+	_, err = b.builder.Sign(mimeType, NewC2PAStreamReaderContext(ctx, input), NewC2PAStreamWriterContext(ctx, output), signer)
 	if err != nil {
+		log.Error("c2pa: signing failed", "error", err)
 		return err
 	}
-	_, err = FromStream(output, mimeType)
+	_, err = FromStreamContext(ctx, output, mimeType)
 	if err != nil {
-		return fmt.Errorf("unable to validate newly-signed file: %w", err)
+		err = fmt.Errorf("unable to validate newly-signed file: %w", err)
+		log.Error("c2pa: post-sign validation failed", "error", err)
+		return err
+	}
+	hooks.AddCount(spanCtx, "c2pa.builder.sign.count", 1)
+	log.Info("c2pa: signing succeeded")
+	return nil
+}
+
+// SignMultiOption configures SignMulti's behavior once one of its
+// extraOutputs fails.
+type SignMultiOption func(*signMultiOptions)
+
+type signMultiOptions struct {
+	abortOnFirstError bool
+}
+
+// WithAbortOnFirstError makes SignMulti stop attempting the remaining
+// extraOutputs as soon as one fails, instead of the default of copying to
+// every output and aggregating all failures into the returned
+// *MultiOutputError.
+func WithAbortOnFirstError() SignMultiOption {
+	return func(o *signMultiOptions) { o.abortOnFirstError = true }
+}
+
+// MultiOutputError reports which of SignMulti's extraOutputs failed,
+// keyed by each failing output's index in the extraOutputs slice.
+type MultiOutputError struct {
+	Errors map[int]error
+}
+
+func (e *MultiOutputError) Error() string {
+	return fmt.Sprintf("c2pa: %d extra output(s) failed: %v", len(e.Errors), e.Errors)
+}
+
+// SignMulti implements Builder.
+func (b *C2PABuilder) SignMulti(input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string, extraOutputs []io.Writer, opts ...SignMultiOption) error {
+	if err := b.Sign(input, output, mimeType); err != nil {
+		return err
+	}
+	return copyToExtraOutputs(output, extraOutputs, opts...)
+}
+
+// copyToExtraOutputs rewinds output and copies it to each of
+// extraOutputs in turn, aggregating failures into a *MultiOutputError
+// rather than stopping at the first one, unless WithAbortOnFirstError was
+// passed. Split out of SignMulti so it can be unit-tested without a real
+// signing pass.
+func copyToExtraOutputs(output io.ReadSeeker, extraOutputs []io.Writer, opts ...SignMultiOption) error {
+	var o signMultiOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	errs := map[int]error{}
+	for i, w := range extraOutputs {
+		var err error
+		if _, seekErr := output.Seek(0, io.SeekStart); seekErr != nil {
+			err = fmt.Errorf("seek signed output for extra output %d: %w", i, seekErr)
+		} else if _, copyErr := io.Copy(w, output); copyErr != nil {
+			err = fmt.Errorf("copy signed output to extra output %d: %w", i, copyErr)
+		}
+		if err == nil {
+			continue
+		}
+		errs[i] = err
+		if o.abortOnFirstError {
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return &MultiOutputError{Errors: errs}
+	}
+	return nil
+}
+
+// SignAsync implements Builder.
+func (b *C2PABuilder) SignAsync(ctx context.Context, input io.ReadSeeker, output io.ReadWriteSeeker, mimeType string) *SignFuture {
+	fut := &SignFuture{done: make(chan struct{})}
+	go func() {
+		defer close(fut.done)
+		fut.err = b.SignContext(ctx, input, output, mimeType)
+	}()
+	return fut
+}
+
+// AddIngredient implements Builder.
+func (b *C2PABuilder) AddIngredient(ingredientJSON string, format string, stream io.ReadSeeker) error {
+	if err := b.builder.AddIngredient(ingredientJSON, format, NewC2PAStreamReader(stream)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddResource implements Builder.
+func (b *C2PABuilder) AddResource(uri string, stream io.ReadSeeker) error {
+	if err := b.builder.AddResource(uri, NewC2PAStreamReader(stream)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FromArchive implements Builder.
+func (b *C2PABuilder) FromArchive(archive io.ReadSeeker) error {
+	if err := b.builder.FromArchive(NewC2PAStreamReader(archive)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ToArchive implements Builder.
+func (b *C2PABuilder) ToArchive(output io.ReadWriteSeeker) error {
+	if err := b.builder.ToArchive(NewC2PAStreamWriter(output)); err != nil {
+		return err
 	}
 	return nil
 }
@@ -1,25 +1,83 @@
 package c2pa
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
+	"fmt"
 
 	rustC2PA "git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/c2pa"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/telemetry"
 )
 
 type C2PACallbackSigner struct {
 	signer    crypto.Signer
 	algorithm SigningAlgorithm
+	// telemetry, if set, receives a span around each Sign invocation. A
+	// nil value disables telemetry for this signer.
+	telemetry *telemetry.Hooks
+	// ctx, if set, bounds how long Sign is willing to wait on the
+	// underlying crypto.Signer. crypto.Signer.Sign itself takes no
+	// context, so a canceled ctx can't abort an in-flight call, but it
+	// does mean Sign stops waiting on it and returns promptly instead of
+	// blocking the cgo callback (and whatever holds the Rust-side lock
+	// it's called under) indefinitely; the abandoned goroutine finishes
+	// on its own and its result is discarded.
+	ctx context.Context
 }
 
-func (s *C2PACallbackSigner) Sign(data []byte) ([]byte, *rustC2PA.Error) {
-	bs, err := s._sign(data)
+// Sign implements the Rust SDK's SignerCallback. A panic from the
+// underlying crypto.Signer is recovered and reported as an Error, since a
+// panic crossing back into Rust through this cgo boundary would abort the
+// whole process rather than unwind normally.
+func (s *C2PACallbackSigner) Sign(data []byte) (result []byte, errOut *rustC2PA.Error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	spanCtx, span := s.telemetry.StartSpan(ctx, "c2pa.SignerCallback.Sign")
+	var spanErr error
+	defer func() {
+		if r := recover(); r != nil {
+			spanErr = fmt.Errorf("panic in Sign: %v", r)
+			result, errOut = nil, rustC2PA.NewErrorSignature(spanErr.Error())
+		}
+		span.End(spanErr)
+	}()
+	if err := ctx.Err(); err != nil {
+		spanErr = err
+		return nil, rustC2PA.NewErrorSignature(fmt.Sprintf("signing canceled before it started: %s", err))
+	}
+
+	bs, err := s.signWithContext(ctx, data)
 	if err != nil {
+		spanErr = err
 		return nil, rustC2PA.NewErrorSignature(err.Error())
 	}
+	s.telemetry.AddCount(spanCtx, "c2pa.signer.sign.count", 1)
 	return bs, nil
 }
 
+// signWithContext runs _sign in a goroutine and returns as soon as either
+// it completes or ctx is done, whichever comes first.
+func (s *C2PACallbackSigner) signWithContext(ctx context.Context, data []byte) ([]byte, error) {
+	type signResult struct {
+		bs  []byte
+		err error
+	}
+	done := make(chan signResult, 1)
+	go func() {
+		bs, err := s._sign(data)
+		done <- signResult{bs, err}
+	}()
+	select {
+	case res := <-done:
+		return res.bs, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("signing canceled: %w", ctx.Err())
+	}
+}
+
 func (s *C2PACallbackSigner) _sign(data []byte) ([]byte, error) {
 	digest, opts, err := s.algorithm.Digest(data)
 	if err != nil {
@@ -0,0 +1,55 @@
+// Package catch provides a small helper for recovering from panics at an
+// FFI/cgo callback boundary, shared by any wrapper whose methods are
+// called from Rust across cgo (e.g. the Stream callbacks in pkg/c2pa/io.go).
+// A panic that unwinds out of a callback invoked from Rust crosses foreign
+// stack frames that don't know how to unwind a Go panic, which aborts the
+// whole process instead of propagating an error normally.
+package catch
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicHandler is invoked by HandlePanic whenever it recovers a panic, so
+// an application can log or record metrics for these events without
+// crashing the process. recovered is the value returned by recover();
+// stack is the goroutine's stack trace at the point of the panic, as
+// captured by runtime/debug.Stack.
+type PanicHandler func(recovered any, stack []byte)
+
+var panicHandler PanicHandler
+
+// SetCallbackPanicHandler registers handler to be called by HandlePanic
+// every time it recovers a panic at an FFI callback boundary. Passing nil
+// clears the handler, which is also the default. Not safe to call
+// concurrently with a panic being recovered.
+func SetCallbackPanicHandler(handler PanicHandler) {
+	panicHandler = handler
+}
+
+// HandlePanic recovers a panic (the value returned by recover()) and, if
+// one occurred, stores an error built via newErr at *errOut, then reports
+// it to the handler registered via SetCallbackPanicHandler (if any), along
+// with the stack trace captured at the point of the panic. where
+// identifies the callback in the resulting message. It's a no-op if r is
+// nil, so the caller's own return value (already set through its normal
+// control flow) is left untouched. newErr is supplied by the caller rather
+// than hardcoded so this package stays free of any dependency on a
+// particular FFI binding's error type. Call it from a defer at the very
+// top of the wrapping function, before any other work:
+//
+//	func (s *Thing) ReadStream(length uint64) (result []byte, errOut *rustC2PA.Error) {
+//	    defer func() { catch.HandlePanic(recover(), &errOut, "ReadStream", rustC2PA.NewErrorIo) }()
+//	    ...
+//	}
+func HandlePanic[E any](r any, errOut *E, where string, newErr func(string) E) {
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	*errOut = newErr(fmt.Sprintf("panic in %s: %v", where, r))
+	if panicHandler != nil {
+		panicHandler(r, stack)
+	}
+}
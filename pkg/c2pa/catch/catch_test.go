@@ -0,0 +1,86 @@
+package catch
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newFakeErr(msg string) error {
+	return fmt.Errorf("fake: %s", msg)
+}
+
+func TestHandlePanicNoPanic(t *testing.T) {
+	var errOut error
+	func() {
+		defer func() { HandlePanic(recover(), &errOut, "Test", newFakeErr) }()
+	}()
+	if errOut != nil {
+		t.Fatalf("expected nil error, got %v", errOut)
+	}
+}
+
+func TestHandlePanicRecovers(t *testing.T) {
+	var errOut error
+	func() {
+		defer func() { HandlePanic(recover(), &errOut, "Test", newFakeErr) }()
+		panic("boom")
+	}()
+	if errOut == nil {
+		t.Fatal("expected a non-nil error after a recovered panic")
+	}
+	want := "fake: panic in Test: boom"
+	if errOut.Error() != want {
+		t.Fatalf("got %q, want %q", errOut.Error(), want)
+	}
+}
+
+func TestHandlePanicCallsRegisteredHandlerWithStack(t *testing.T) {
+	defer SetCallbackPanicHandler(nil)
+
+	var gotRecovered any
+	var gotStack []byte
+	SetCallbackPanicHandler(func(recovered any, stack []byte) {
+		gotRecovered = recovered
+		gotStack = stack
+	})
+
+	var errOut error
+	func() {
+		defer func() { HandlePanic(recover(), &errOut, "Test", newFakeErr) }()
+		panic("boom")
+	}()
+
+	if gotRecovered != "boom" {
+		t.Fatalf("got recovered %v, want %q", gotRecovered, "boom")
+	}
+	if len(gotStack) == 0 {
+		t.Fatal("expected a non-empty stack trace")
+	}
+}
+
+func TestHandlePanicSkipsHandlerWhenNoPanic(t *testing.T) {
+	defer SetCallbackPanicHandler(nil)
+
+	called := false
+	SetCallbackPanicHandler(func(recovered any, stack []byte) { called = true })
+
+	var errOut error
+	func() {
+		defer func() { HandlePanic(recover(), &errOut, "Test", newFakeErr) }()
+	}()
+
+	if called {
+		t.Fatal("handler should not be called when there was no panic")
+	}
+}
+
+func TestHandlePanicDoesNotClobberExistingValue(t *testing.T) {
+	sentinel := fmt.Errorf("already set")
+	errOut := sentinel
+	func() {
+		defer func() { HandlePanic(recover(), &errOut, "Test", newFakeErr) }()
+	}()
+	if errOut != sentinel {
+		t.Fatalf("expected existing value to survive a no-panic call, got %v", errOut)
+	}
+}
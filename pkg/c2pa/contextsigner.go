@@ -0,0 +1,41 @@
+package c2pa
+
+import (
+	"context"
+	"crypto"
+	"io"
+)
+
+// ContextSigner is crypto.Signer plus a context-aware signing method, for
+// backends (HSM, KMS, other cloud signing services) whose Sign call is a
+// network round-trip that should honor caller deadlines and cancellation.
+type ContextSigner interface {
+	Public() crypto.PublicKey
+	SignContext(ctx context.Context, rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+}
+
+// contextSignerAdapter adapts a ContextSigner to plain crypto.Signer by
+// binding it to a fixed context, so it can be used as BuilderParams.Signer,
+// whose crypto.Signer Sign method takes no context to pass through.
+type contextSignerAdapter struct {
+	ctx    context.Context
+	signer ContextSigner
+}
+
+// NewContextSignerAdapter returns a crypto.Signer that delegates to signer
+// using ctx for every Sign call. Use a context with a deadline appropriate
+// for the remote signing service; a canceled or expired ctx causes Sign to
+// fail immediately rather than blocking on the network.
+func NewContextSignerAdapter(ctx context.Context, signer ContextSigner) crypto.Signer {
+	return &contextSignerAdapter{ctx: ctx, signer: signer}
+}
+
+func (a *contextSignerAdapter) Public() crypto.PublicKey {
+	return a.signer.Public()
+}
+
+func (a *contextSignerAdapter) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return a.signer.SignContext(a.ctx, rand, digest, opts)
+}
+
+var _ crypto.Signer = (*contextSignerAdapter)(nil)
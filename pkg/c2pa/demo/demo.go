@@ -1,107 +1,371 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
 
 	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/pki"
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/signers"
 )
 
+// Start dispatches to one of the sign/verify/inspect/csr subcommands,
+// each with its own flag.FlagSet, the same shape pkictl and similar PKI
+// CLIs use.
 func Start() error {
-	fs := flag.NewFlagSet("c2pa-go-demo", flag.ExitOnError)
-	manifest := fs.String("manifest", "", "manifest file for signing")
+	if len(os.Args) < 2 {
+		printUsage()
+		return nil
+	}
+	sub, args := os.Args[1], os.Args[2:]
+	switch sub {
+	case "sign":
+		return cmdSign(args)
+	case "verify":
+		return cmdVerify(args)
+	case "inspect":
+		return cmdInspect(args)
+	case "csr":
+		return cmdCSR(args)
+	default:
+		printUsage()
+		return fmt.Errorf("unknown subcommand %q", sub)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <sign|verify|inspect|csr> [flags]\n", os.Args[0])
+}
+
+// cmdSign implements the "sign" subcommand: embed manifest into input and
+// write the signed result to output.
+func cmdSign(args []string) error {
+	fs := flag.NewFlagSet("c2pa-go-demo sign", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "manifest file for signing")
 	cert := fs.String("cert", "", "certificate file to use")
 	key := fs.String("key", "", "private key file to use")
 	input := fs.String("input", "", "input file for signing")
 	output := fs.String("output", "", "output file for signing")
 	alg := fs.String("alg", "", "algorithm to use to sign (es256, es256k, es384, es512, ps256, ps384, ps512, ed25519)")
-	pass := os.Args[1:]
-	err := fs.Parse(pass)
-	if err != nil {
+	kms := fs.String("kms", "", "KMS/HSM key URI to sign with instead of --key/--alg (see pkg/c2pa/signers)")
+	if err := fs.Parse(args); err != nil {
 		return err
 	}
-	if *manifest != "" || *output != "" {
-		if *manifest == "" {
-			return fmt.Errorf("missing --manifest")
-		}
-		if *output == "" {
-			return fmt.Errorf("missing --output")
-		}
-		if *input == "" {
-			return fmt.Errorf("missing --input")
-		}
-		if *cert == "" {
-			return fmt.Errorf("missing --cert")
-		}
+
+	if *manifestPath == "" {
+		return fmt.Errorf("missing --manifest")
+	}
+	if *output == "" {
+		return fmt.Errorf("missing --output")
+	}
+	if *input == "" {
+		return fmt.Errorf("missing --input")
+	}
+	if *cert == "" {
+		return fmt.Errorf("missing --cert")
+	}
+	if *kms == "" {
 		if *key == "" {
 			return fmt.Errorf("missing --key")
 		}
 		if *alg == "" {
 			return fmt.Errorf("missing --alg")
 		}
-		certBytes, err := os.ReadFile(*cert)
+	}
+
+	certBytes, err := os.ReadFile(*cert)
+	if err != nil {
+		return err
+	}
+	manifestBytes, err := os.ReadFile(*manifestPath)
+	if err != nil {
+		return err
+	}
+	var manifest c2pa.ManifestDefinition
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return err
+	}
+
+	params := &c2pa.BuilderParams{
+		Cert:  certBytes,
+		TAURL: "http://timestamp.digicert.com",
+	}
+	if *kms != "" {
+		signer, signerAlg, err := signers.NewKMSSigner(*kms)
 		if err != nil {
 			return err
 		}
+		params.Signer = signer
+		params.Algorithm = signerAlg
+	} else {
 		keyBytes, err := os.ReadFile(*key)
 		if err != nil {
 			return err
 		}
-		manifestBytes, err := os.ReadFile(*manifest)
+		signer, err := c2pa.MakeStaticSigner(keyBytes)
 		if err != nil {
 			return err
 		}
-		var manifest c2pa.ManifestDefinition
-		err = json.Unmarshal(manifestBytes, &manifest)
+		signerAlg, err := c2pa.GetSigningAlgorithm(*alg)
 		if err != nil {
 			return err
 		}
-		b, err := c2pa.NewBuilder(&manifest, &c2pa.BuilderParams{
-			Cert:      certBytes,
-			Key:       keyBytes,
-			Algorithm: *alg,
-			TAURL:     "http://timestamp.digicert.com",
+		params.Signer = signer
+		params.Algorithm = signerAlg
+	}
+
+	b, err := c2pa.NewBuilder(&manifest, params)
+	if err != nil {
+		return err
+	}
+	return b.SignFile(*input, *output)
+}
+
+// trustConfig is the JSON shape of --trust-config: an allowlist of
+// extended key usages and/or signing certificate fingerprints, mirroring
+// the C2PA trust list model.
+type trustConfig struct {
+	AllowedEKUs       []string `json:"allowedEKUs"`
+	AllowedCertSHA256 []string `json:"allowedCertSHA256"`
+}
+
+var ekuByName = map[string]x509.ExtKeyUsage{
+	"any":             x509.ExtKeyUsageAny,
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+// verifyReport is the machine-readable result cmdVerify prints, success or
+// failure alike.
+type verifyReport struct {
+	Target   string                  `json:"target"`
+	Error    string                  `json:"error,omitempty"`
+	Statuses []c2pa.ValidationStatus `json:"statuses"`
+}
+
+// cmdVerify implements the "verify" subcommand: walk the provenance cert
+// chain against --trust-anchors (and, if given, restrict it further via
+// --trust-config), printing a JSON report and exiting non-zero on any
+// validation failure.
+func cmdVerify(args []string) error {
+	fs := flag.NewFlagSet("c2pa-go-demo verify", flag.ExitOnError)
+	trustAnchors := fs.String("trust-anchors", "", "PEM bundle of trusted root certificates")
+	trustConfigPath := fs.String("trust-config", "", "JSON file listing allowed EKUs / signing cert SHA-256s")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	targets := fs.Args()
+	if *trustAnchors == "" || len(targets) != 1 {
+		fs.Usage()
+		return fmt.Errorf("usage: c2pa-go-demo verify --trust-anchors <pem> [--trust-config <json>] <target-file>")
+	}
+
+	anchorBytes, err := os.ReadFile(*trustAnchors)
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(anchorBytes) {
+		return fmt.Errorf("no certificates found in %s", *trustAnchors)
+	}
+	// Clock is left unset: VerifyProvenance resolves chain validity against
+	// the manifest's own signing time when one is recorded, only falling
+	// back to wall-clock time when it isn't.
+	policy := &c2pa.TrustPolicy{Roots: roots}
+
+	var allowedSHA256 map[string]bool
+	if *trustConfigPath != "" {
+		cfgBytes, err := os.ReadFile(*trustConfigPath)
+		if err != nil {
+			return err
+		}
+		var cfg trustConfig
+		if err := json.Unmarshal(cfgBytes, &cfg); err != nil {
+			return fmt.Errorf("parse --trust-config: %w", err)
+		}
+		for _, name := range cfg.AllowedEKUs {
+			eku, ok := ekuByName[name]
+			if !ok {
+				return fmt.Errorf("unknown EKU name %q in --trust-config", name)
+			}
+			policy.AllowedEKUs = append(policy.AllowedEKUs, eku)
+		}
+		if len(cfg.AllowedCertSHA256) > 0 {
+			allowedSHA256 = make(map[string]bool, len(cfg.AllowedCertSHA256))
+			for _, sum := range cfg.AllowedCertSHA256 {
+				allowedSHA256[strings.ToLower(sum)] = true
+			}
+		}
+	}
+
+	reader, statuses, verr := c2pa.VerifyFile(targets[0], policy)
+	report := verifyReport{Target: targets[0]}
+	if verr != nil {
+		report.Error = verr.Error()
+	}
+	if reader != nil && len(allowedSHA256) > 0 && !leafSHA256Allowed(reader.GetProvenanceCertChain(), allowedSHA256) {
+		statuses = append(statuses, c2pa.ValidationStatus{
+			Code:        "signingCredential.untrusted",
+			Explanation: "leaf certificate SHA-256 is not in --trust-config's allowed list",
 		})
+	}
+	report.Statuses = statuses
+
+	bs, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bs))
+
+	if report.Error != "" || len(statuses) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func leafSHA256Allowed(certChainPEM string, allowed map[string]bool) bool {
+	block, _ := pem.Decode([]byte(certChainPEM))
+	if block == nil {
+		return false
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return allowed[hex.EncodeToString(sum[:])]
+}
+
+// cmdInspect implements the "inspect" subcommand: dump the active
+// manifest (or, with --assertion, just one labeled assertion within it)
+// as JSON or CBOR.
+func cmdInspect(args []string) error {
+	fs := flag.NewFlagSet("c2pa-go-demo inspect", flag.ExitOnError)
+	assertion := fs.String("assertion", "", "print only the assertion with this label")
+	format := fs.String("format", "json", "output format: json or cbor")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	targets := fs.Args()
+	if len(targets) != 1 {
+		fs.Usage()
+		return fmt.Errorf("usage: c2pa-go-demo inspect [--assertion <label>] [--format json|cbor] <target-file>")
+	}
+
+	reader, err := c2pa.FromFile(targets[0])
+	if err != nil {
+		return err
+	}
+	activeManifest := reader.GetActiveManifest()
+	if activeManifest == nil {
+		return fmt.Errorf("could not find active manifest")
+	}
+
+	var out any = activeManifest
+	if *assertion != "" {
+		out, err = findAssertion(activeManifest, *assertion)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch *format {
+	case "cbor":
+		bs, err := cbor.Marshal(out)
 		if err != nil {
 			return err
 		}
-		err = b.SignFile(*input, *output)
+		_, err = os.Stdout.Write(bs)
+		return err
+	case "json", "":
+		bs, err := json.MarshalIndent(out, "", "  ")
 		if err != nil {
 			return err
 		}
+		fmt.Println(string(bs))
 		return nil
+	default:
+		return fmt.Errorf("unknown --format %q (want json or cbor)", *format)
+	}
+}
+
+func findAssertion(manifest any, label string) (any, error) {
+	bs, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var decoded struct {
+		Assertions []struct {
+			Label string          `json:"label"`
+			Data  json.RawMessage `json:"data"`
+		} `json:"assertions"`
+	}
+	if err := json.Unmarshal(bs, &decoded); err != nil {
+		return nil, err
+	}
+	for _, a := range decoded.Assertions {
+		if a.Label == label {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("no assertion with label %q", label)
+}
+
+// cmdCSR implements the "csr" subcommand: generate a PEM CSR for an
+// existing private key, ready to send to a C2PA-conformant CA.
+func cmdCSR(args []string) error {
+	fs := flag.NewFlagSet("c2pa-go-demo csr", flag.ExitOnError)
+	key := fs.String("key", "", "private key file (PEM) to generate a CSR for")
+	out := fs.String("out", "", "output path for the PEM-encoded CSR")
+	cn := fs.String("cn", "", "subject common name")
+	org := fs.String("org", "", "subject organization")
+	dns := fs.String("dns", "", "comma-separated subject alternative DNS names")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	args := fs.Args()
-	if len(args) != 1 {
+	if *key == "" || *out == "" {
 		fs.Usage()
-		fmt.Printf("usage: %s [target-file]\n", os.Args[0])
-		return nil
+		return fmt.Errorf("missing --key or --out")
+	}
+
+	keyBytes, err := os.ReadFile(*key)
+	if err != nil {
+		return err
 	}
-	fname := args[0]
-	reader, err := c2pa.FromFile(fname)
+	signer, err := c2pa.MakeStaticSigner(keyBytes)
 	if err != nil {
 		return err
 	}
 
-	activeManifest := reader.GetActiveManifest()
-	if activeManifest == nil {
-		return fmt.Errorf("could not find active manifest")
+	subject := pkix.Name{CommonName: *cn}
+	if *org != "" {
+		subject.Organization = []string{*org}
+	}
+	var sans pki.CSROptions
+	if *dns != "" {
+		sans.DNSNames = strings.Split(*dns, ",")
 	}
 
-	bs, err := json.MarshalIndent(activeManifest, "", "  ")
+	csrBytes, err := pki.GenerateCSR(signer, subject, sans)
 	if err != nil {
 		return err
 	}
-
-	fmt.Println(string(bs))
-	return nil
+	return os.WriteFile(*out, csrBytes, 0644)
 }
 
 func main() {
-	err := Start()
-	if err != nil {
-		panic(err)
+	if err := Start(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
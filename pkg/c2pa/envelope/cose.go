@@ -0,0 +1,253 @@
+package envelope
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// COSE header labels used by this package (RFC 8152 / RFC 9360).
+const (
+	coseHeaderAlg     = 1
+	coseHeaderX5chain = 33
+	coseHeaderX5t     = 34
+	coseHeaderTstoken = "timestamp" // not IANA-registered; c2pa-go specific
+)
+
+// COSESign1 is a COSE_Sign1 envelope (RFC 8152 section 4.2) that carries
+// its payload inline.
+type COSESign1 struct {
+	Algorithm string
+	CertChain [][]byte // DER, leaf first
+	Timestamp []byte   // DER-encoded RFC3161 TST, if any
+	Payload   []byte
+	Signature []byte
+}
+
+// coseHeaderMap mirrors the CBOR map used for COSE protected/unprotected
+// headers; field order doesn't matter for our maps, so a Go map works.
+type coseHeaderMap map[interface{}]interface{}
+
+// cborSign1 is the 4-element COSE_Sign1 array: [protected, unprotected,
+// payload, signature].
+type cborSign1 struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected coseHeaderMap
+	Payload     []byte
+	Signature   []byte
+}
+
+// sigStructure is the Sig_structure used to build the signing/verification
+// input (RFC 8152 section 4.4), tagged "Signature1" for COSE_Sign1.
+type sigStructure struct {
+	_             struct{} `cbor:",toarray"`
+	Context       string
+	BodyProtected []byte
+	ExternalAAD   []byte
+	Payload       []byte
+}
+
+// SignCOSE builds a COSE_Sign1 envelope over payload, signing with signer
+// per algorithm. certChain is DER-encoded, leaf first.
+func SignCOSE(signer crypto.Signer, algorithm string, payload []byte, certChain [][]byte, timestamp []byte) (*COSESign1, error) {
+	alg, err := coseAlgFor(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := cbor.Marshal(coseHeaderMap{coseHeaderAlg: alg})
+	if err != nil {
+		return nil, fmt.Errorf("envelope: marshal COSE protected header: %w", err)
+	}
+
+	toSign, err := cbor.Marshal(sigStructure{
+		Context:       "Signature1",
+		BodyProtected: protected,
+		ExternalAAD:   []byte{},
+		Payload:       payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("envelope: marshal COSE Sig_structure: %w", err)
+	}
+
+	digestBs, opts, err := digest(algorithm, toSign)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(rand.Reader, digestBs, opts)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: sign COSE_Sign1: %w", err)
+	}
+	// Same conversion SignJWS does and for the same reason: RFC 8152
+	// section 8.1 requires fixed-width R||S, not the ASN.1 DER
+	// crypto.Signer.Sign returns.
+	if pub, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		sig, err = ecdsaSigToRS(sig, pub.Curve.Params().BitSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &COSESign1{
+		Algorithm: algorithm,
+		CertChain: certChain,
+		Timestamp: timestamp,
+		Payload:   payload,
+		Signature: sig,
+	}, nil
+}
+
+// Marshal encodes the envelope as a CBOR COSE_Sign1 structure.
+func (c *COSESign1) Marshal() ([]byte, error) {
+	alg, err := coseAlgFor(c.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := cbor.Marshal(coseHeaderMap{coseHeaderAlg: alg})
+	if err != nil {
+		return nil, err
+	}
+	unprotected := coseHeaderMap{}
+	if len(c.CertChain) > 0 {
+		if len(c.CertChain) == 1 {
+			unprotected[coseHeaderX5chain] = c.CertChain[0]
+		} else {
+			unprotected[coseHeaderX5chain] = c.CertChain
+		}
+		unprotected[coseHeaderX5t] = sha256Sum(c.CertChain[0])
+	}
+	if len(c.Timestamp) > 0 {
+		unprotected[coseHeaderTstoken] = c.Timestamp
+	}
+	return cbor.Marshal(cborSign1{
+		Protected:   protected,
+		Unprotected: unprotected,
+		Payload:     c.Payload,
+		Signature:   c.Signature,
+	})
+}
+
+// ParseCOSESign1 decodes a CBOR COSE_Sign1 structure produced by Marshal.
+func ParseCOSESign1(data []byte) (*COSESign1, error) {
+	var raw cborSign1
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("envelope: parse COSE_Sign1: %w", err)
+	}
+
+	var protected coseHeaderMap
+	if err := cbor.Unmarshal(raw.Protected, &protected); err != nil {
+		return nil, fmt.Errorf("envelope: parse COSE protected header: %w", err)
+	}
+	algNum, ok := protected[uint64(coseHeaderAlg)]
+	if !ok {
+		algNum, ok = protected[int64(coseHeaderAlg)]
+	}
+	if !ok {
+		return nil, fmt.Errorf("envelope: COSE protected header missing alg")
+	}
+	algorithm, err := algorithmForCoseAlg(toInt64(algNum))
+	if err != nil {
+		return nil, err
+	}
+
+	env := &COSESign1{Algorithm: algorithm, Payload: raw.Payload, Signature: raw.Signature}
+	if v, ok := lookupHeader(raw.Unprotected, coseHeaderX5chain); ok {
+		switch chain := v.(type) {
+		case [][]byte:
+			env.CertChain = chain
+		case []byte:
+			env.CertChain = [][]byte{chain}
+		case []interface{}:
+			for _, item := range chain {
+				b, ok := item.([]byte)
+				if !ok {
+					return nil, fmt.Errorf("envelope: x5chain entry is not bytes")
+				}
+				env.CertChain = append(env.CertChain, b)
+			}
+		}
+	}
+	if v, ok := lookupHeader(raw.Unprotected, coseHeaderTstoken); ok {
+		if b, ok := v.([]byte); ok {
+			env.Timestamp = b
+		}
+	}
+	return env, nil
+}
+
+func lookupHeader(m coseHeaderMap, key interface{}) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	switch k := key.(type) {
+	case int:
+		if v, ok := m[int64(k)]; ok {
+			return v, true
+		}
+		if v, ok := m[uint64(k)]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case uint64:
+		return int64(n)
+	case int:
+		return int64(n)
+	}
+	return 0
+}
+
+// Verify checks the envelope's signature against the leaf certificate in
+// its cert chain, and returns the parsed chain for the caller to evaluate
+// against its own trust policy.
+func (c *COSESign1) Verify() ([]*x509.Certificate, error) {
+	if len(c.CertChain) == 0 {
+		return nil, fmt.Errorf("envelope: COSE_Sign1 has no x5chain to verify against")
+	}
+	chain := make([]*x509.Certificate, len(c.CertChain))
+	for i, der := range c.CertChain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: parse cert chain[%d]: %w", i, err)
+		}
+		chain[i] = cert
+	}
+
+	alg, err := coseAlgFor(c.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := cbor.Marshal(coseHeaderMap{coseHeaderAlg: alg})
+	if err != nil {
+		return nil, err
+	}
+	toVerify, err := cbor.Marshal(sigStructure{
+		Context:       "Signature1",
+		BodyProtected: protected,
+		ExternalAAD:   []byte{},
+		Payload:       c.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(chain[0].PublicKey, c.Algorithm, toVerify, c.Signature); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
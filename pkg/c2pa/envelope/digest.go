@@ -0,0 +1,43 @@
+package envelope
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+)
+
+// digest mirrors c2pa.SigningAlgorithm.Digest: it hashes data as required
+// by algorithm and returns the crypto.SignerOpts a crypto.Signer needs to
+// produce a signature over it. Duplicated here (rather than imported) so
+// this package has no dependency on the cgo-backed c2pa package and can be
+// built and tested on its own.
+func digest(algorithm string, data []byte) ([]byte, crypto.SignerOpts, error) {
+	switch algorithm {
+	case "ed25519":
+		return data, crypto.Hash(0), nil
+	case "es256", "es256k":
+		return hashWith(crypto.SHA256, data), crypto.SHA256, nil
+	case "es384":
+		return hashWith(crypto.SHA384, data), crypto.SHA384, nil
+	case "es512":
+		return hashWith(crypto.SHA512, data), crypto.SHA512, nil
+	case "ps256":
+		return hashWith(crypto.SHA256, data), pssOpts(crypto.SHA256), nil
+	case "ps384":
+		return hashWith(crypto.SHA384, data), pssOpts(crypto.SHA384), nil
+	case "ps512":
+		return hashWith(crypto.SHA512, data), pssOpts(crypto.SHA512), nil
+	default:
+		return nil, nil, fmt.Errorf("envelope: unknown algorithm: %s", algorithm)
+	}
+}
+
+func hashWith(h crypto.Hash, data []byte) []byte {
+	hasher := h.New()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
+
+func pssOpts(h crypto.Hash) *rsa.PSSOptions {
+	return &rsa.PSSOptions{Hash: h, SaltLength: rsa.PSSSaltLengthEqualsHash}
+}
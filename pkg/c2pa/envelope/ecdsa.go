@@ -0,0 +1,64 @@
+package envelope
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// asn1ECDSASignature mirrors the ASN.1 Ecdsa-Sig-Value crypto.Signer
+// produces for an ECDSA key (SEQUENCE { r INTEGER, s INTEGER }).
+type asn1ECDSASignature struct {
+	R, S *big.Int
+}
+
+// ecdsaSigSize returns the fixed byte width RFC 7518 section 3.4 (JWS) and
+// RFC 8152 section 8.1 (COSE) both require for each of R and S when
+// concatenating an ECDSA signature for a curve with the given bit size,
+// e.g. 32 for P-256, 48 for P-384, 66 for P-521.
+func ecdsaSigSize(curveBits int) int {
+	return (curveBits + 7) / 8
+}
+
+// ecdsaSigToRS converts der, the ASN.1 DER signature crypto.Signer.Sign
+// produces for an ECDSA key, into the fixed-width big-endian R||S
+// concatenation JWS and COSE require. Neither format accepts the variable-
+// length ASN.1 DER encoding directly.
+func ecdsaSigToRS(der []byte, curveBits int) ([]byte, error) {
+	var sig asn1ECDSASignature
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("envelope: parse ASN.1 ECDSA signature: %w", err)
+	}
+	size := ecdsaSigSize(curveBits)
+	if sig.R.BitLen() > size*8 || sig.S.BitLen() > size*8 {
+		return nil, fmt.Errorf("envelope: ECDSA signature component too large for a %d-bit curve", curveBits)
+	}
+	out := make([]byte, 2*size)
+	sig.R.FillBytes(out[:size])
+	sig.S.FillBytes(out[size:])
+	return out, nil
+}
+
+// rsToECDSASig converts rs, a fixed-width big-endian R||S concatenation as
+// produced by ecdsaSigToRS, back into R and S for ecdsa.Verify.
+func rsToECDSASig(rs []byte) (r, s *big.Int, err error) {
+	if len(rs)%2 != 0 || len(rs) == 0 {
+		return nil, nil, fmt.Errorf("envelope: malformed R||S ECDSA signature of length %d", len(rs))
+	}
+	half := len(rs) / 2
+	return new(big.Int).SetBytes(rs[:half]), new(big.Int).SetBytes(rs[half:]), nil
+}
+
+// verifyECDSARS verifies sig, a fixed-width R||S signature, against pub and
+// digest.
+func verifyECDSARS(pub *ecdsa.PublicKey, digest, sig []byte) error {
+	r, s, err := rsToECDSASig(sig)
+	if err != nil {
+		return err
+	}
+	if !ecdsa.Verify(pub, digest, r, s) {
+		return fmt.Errorf("envelope: ecdsa signature verification failed")
+	}
+	return nil
+}
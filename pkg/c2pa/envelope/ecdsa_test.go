@@ -0,0 +1,90 @@
+package envelope
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEcdsaSigToRSRoundTrips(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := []byte("digest-shaped bytes, not actually hashed")
+
+	der, err := key.Sign(rand.Reader, digest, nil)
+	require.NoError(t, err)
+
+	rs, err := ecdsaSigToRS(der, key.Curve.Params().BitSize)
+	require.NoError(t, err)
+	require.Len(t, rs, 64) // P-256: 32 bytes R + 32 bytes S
+
+	require.NoError(t, verifyECDSARS(&key.PublicKey, digest, rs))
+}
+
+// TestEcdsaSigToRSIsRawFixedWidthNotASN1 checks the output shape directly,
+// independent of this package's own rsToECDSASig/verifyECDSARS: RFC 7518
+// section 3.4 / RFC 8152 section 8.1 both require exactly 2*size bytes of
+// raw big-endian R||S, never an ASN.1 SEQUENCE tag/length byte, which is
+// what crypto.Signer.Sign returns and what the old code shipped unmodified.
+func TestEcdsaSigToRSIsRawFixedWidthNotASN1(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := []byte("another digest-shaped value")
+
+	der, err := key.Sign(rand.Reader, digest, nil)
+	require.NoError(t, err)
+	rs, err := ecdsaSigToRS(der, key.Curve.Params().BitSize)
+	require.NoError(t, err)
+
+	require.Equal(t, 64, len(rs), "fixed-width R||S must always be exactly 2*size bytes")
+	require.NotEqual(t, der[0], rs[0], "ASN.1 DER starts with a 0x30 SEQUENCE tag; raw R||S must not")
+
+	// Independently reconstruct R and S with math/big (no call into this
+	// package's own parsing) and verify with crypto/ecdsa.Verify directly,
+	// the same way a standard-library-only JOSE/COSE consumer would.
+	half := len(rs) / 2
+	r := new(big.Int).SetBytes(rs[:half])
+	s := new(big.Int).SetBytes(rs[half:])
+	require.True(t, ecdsa.Verify(&key.PublicKey, digest, r, s))
+}
+
+func TestEcdsaSigSizePerCurve(t *testing.T) {
+	require.Equal(t, 32, ecdsaSigSize(elliptic.P256().Params().BitSize))
+	require.Equal(t, 48, ecdsaSigSize(elliptic.P384().Params().BitSize))
+	require.Equal(t, 66, ecdsaSigSize(elliptic.P521().Params().BitSize))
+}
+
+func TestJWSSignatureIsFixedWidthRS(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		curve elliptic.Curve
+		alg   string
+		size  int
+	}{
+		{"P256", elliptic.P256(), "es256", 32},
+		{"P384", elliptic.P384(), "es384", 48},
+		{"P521", elliptic.P521(), "es512", 66},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			key, err := ecdsa.GenerateKey(c.curve, rand.Reader)
+			require.NoError(t, err)
+
+			jws, err := SignJWS(key, c.alg, []byte("payload"), nil, nil)
+			require.NoError(t, err)
+			require.Len(t, jws.Signature, 2*c.size)
+		})
+	}
+}
+
+func TestCOSESignatureIsFixedWidthRS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	env, err := SignCOSE(key, "es256", []byte("payload"), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, env.Signature, 64)
+}
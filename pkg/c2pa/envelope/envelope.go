@@ -0,0 +1,80 @@
+// Package envelope builds and parses detached signature envelopes for a
+// C2PA claim signature: JWS (RFC 7515) and COSE_Sign1 (RFC 8152). These let
+// callers carry a claim signature alongside the asset — in a sidecar file,
+// a registry, or a manifest repository — instead of only embedding it.
+//
+// This package signs and verifies envelopes over caller-supplied bytes; it
+// has no opinion on what those bytes represent (in c2pa-go, the C2PA claim
+// bytes produced by Builder). Algorithm selection uses the same algorithm
+// names as c2pa.SigningAlgorithmName ("es256", "ps384", ...) so callers can
+// pass that value straight through without an import cycle.
+package envelope
+
+import "fmt"
+
+// joseAlg is the JOSE "alg" header value for each C2PA signing algorithm.
+var joseAlg = map[string]string{
+	"ed25519": "EdDSA",
+	"es256":   "ES256",
+	"es256k":  "ES256K", // RFC 8812
+	"es384":   "ES384",
+	"es512":   "ES512",
+	"ps256":   "PS256",
+	"ps384":   "PS384",
+	"ps512":   "PS512",
+}
+
+// coseAlg is the COSE algorithm identifier (IANA "COSE Algorithms"
+// registry) for each C2PA signing algorithm.
+var coseAlg = map[string]int64{
+	"ed25519": -8,
+	"es256":   -7,
+	"es256k":  -47, // RFC 8812
+	"es384":   -35,
+	"es512":   -36,
+	"ps256":   -37,
+	"ps384":   -38,
+	"ps512":   -39,
+}
+
+func joseAlgFor(algorithm string) (string, error) {
+	alg, ok := joseAlg[algorithm]
+	if !ok {
+		return "", fmt.Errorf("envelope: no JOSE alg mapping for algorithm %q", algorithm)
+	}
+	return alg, nil
+}
+
+func coseAlgFor(algorithm string) (int64, error) {
+	alg, ok := coseAlg[algorithm]
+	if !ok {
+		return 0, fmt.Errorf("envelope: no COSE alg mapping for algorithm %q", algorithm)
+	}
+	return alg, nil
+}
+
+func algorithmForJoseAlg(alg string) (string, error) {
+	for k, v := range joseAlg {
+		if v == alg {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("envelope: unrecognized JOSE alg %q", alg)
+}
+
+func algorithmForCoseAlg(alg int64) (string, error) {
+	for k, v := range coseAlg {
+		if v == alg {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("envelope: unrecognized COSE alg %d", alg)
+}
+
+// Signer produces a raw signature over data, as crypto.Signer.Sign would
+// after the caller has already applied any required digest/PSS options for
+// its algorithm. Nothing in this module implements it; SignJWS and SignCOSE
+// take a crypto.Signer instead.
+type Signer interface {
+	SignEnvelope(data []byte) ([]byte, error)
+}
@@ -0,0 +1,87 @@
+package envelope
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func selfSignedCert(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "envelope-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return der
+}
+
+func TestJWSRoundTrip(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cert := selfSignedCert(t, key)
+
+	payload := []byte("c2pa claim bytes")
+	jws, err := SignJWS(key, "es256", payload, [][]byte{cert}, []byte("fake-tst"))
+	require.NoError(t, err)
+
+	compact, err := jws.Compact()
+	require.NoError(t, err)
+	parsed, err := ParseJWSCompact(compact)
+	require.NoError(t, err)
+	chain, err := parsed.Verify()
+	require.NoError(t, err)
+	require.Len(t, chain, 1)
+
+	jsonBs, err := jws.JSON()
+	require.NoError(t, err)
+	parsedJSON, err := ParseJWSJSON(jsonBs)
+	require.NoError(t, err)
+	require.Equal(t, []byte("fake-tst"), parsedJSON.Timestamp)
+	_, err = parsedJSON.Verify()
+	require.NoError(t, err)
+}
+
+func TestCOSESign1RoundTrip(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cert := selfSignedCert(t, key)
+
+	payload := []byte("c2pa claim bytes")
+	env, err := SignCOSE(key, "es256", payload, [][]byte{cert}, nil)
+	require.NoError(t, err)
+
+	encoded, err := env.Marshal()
+	require.NoError(t, err)
+
+	parsed, err := ParseCOSESign1(encoded)
+	require.NoError(t, err)
+	require.Equal(t, "es256", parsed.Algorithm)
+
+	chain, err := parsed.Verify()
+	require.NoError(t, err)
+	require.Len(t, chain, 1)
+}
+
+func TestJWSVerifyRejectsTamperedPayload(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cert := selfSignedCert(t, key)
+
+	jws, err := SignJWS(key, "es256", []byte("original"), [][]byte{cert}, nil)
+	require.NoError(t, err)
+	jws.Payload = []byte("tampered")
+
+	_, err = jws.Verify()
+	require.Error(t, err)
+}
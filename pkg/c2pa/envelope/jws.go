@@ -0,0 +1,260 @@
+package envelope
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// JWSHeader is the JOSE protected header carried by a JWS envelope.
+type JWSHeader struct {
+	Alg string   `json:"alg"`
+	X5c []string `json:"x5c"`
+}
+
+// JWS is a JWS envelope over a C2PA claim signature, with the payload
+// carried inline in both serializations. It
+// supports both compact and JSON serialization; Timestamp is only carried
+// by the JSON serialization, as an unprotected header, since JWS compact
+// serialization has no place for unprotected headers.
+type JWS struct {
+	Header    JWSHeader
+	Payload   []byte
+	Signature []byte
+	// Timestamp is a DER-encoded RFC3161 TST over Payload, if present.
+	Timestamp []byte
+}
+
+// jwsJSON is the flattened JSON serialization (RFC 7515 section 7.2.2) for a
+// single-signature JWS.
+type jwsJSON struct {
+	Payload   string          `json:"payload"`
+	Protected string          `json:"protected"`
+	Header    *jwsUnprotected `json:"header,omitempty"`
+	Signature string          `json:"signature"`
+}
+
+type jwsUnprotected struct {
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// SignJWS builds a JWS envelope over payload, signing with signer per
+// algorithm (a C2PA SigningAlgorithmName such as "es256"). certChain is a
+// DER-encoded certificate chain, leaf first, carried in the x5c header.
+func SignJWS(signer crypto.Signer, algorithm string, payload []byte, certChain [][]byte, timestamp []byte) (*JWS, error) {
+	alg, err := joseAlgFor(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	header := JWSHeader{Alg: alg, X5c: make([]string, len(certChain))}
+	for i, der := range certChain {
+		header.X5c[i] = base64.StdEncoding.EncodeToString(der)
+	}
+	headerBs, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: marshal JWS header: %w", err)
+	}
+
+	signingInput := encodeSegment(headerBs) + "." + encodeSegment(payload)
+	digestBs, opts, err := digest(algorithm, []byte(signingInput))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(rand.Reader, digestBs, opts)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: sign JWS: %w", err)
+	}
+	// crypto.Signer.Sign returns ECDSA signatures as ASN.1 DER, but RFC
+	// 7518 section 3.4 requires the fixed-width big-endian R||S
+	// concatenation instead; without this conversion any standard
+	// JOSE library rejects the signature outright.
+	if pub, ok := signer.Public().(*ecdsa.PublicKey); ok {
+		sig, err = ecdsaSigToRS(sig, pub.Curve.Params().BitSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &JWS{Header: header, Payload: payload, Signature: sig, Timestamp: timestamp}, nil
+}
+
+// Compact returns the JWS compact serialization: protected-header.payload.signature.
+// The timestamp, if any, is not representable in compact form.
+func (j *JWS) Compact() (string, error) {
+	headerBs, err := json.Marshal(j.Header)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s.%s", encodeSegment(headerBs), encodeSegment(j.Payload), encodeSegment(j.Signature)), nil
+}
+
+// JSON returns the JWS flattened JSON serialization, with the timestamp (if
+// any) carried as an unprotected header.
+func (j *JWS) JSON() ([]byte, error) {
+	headerBs, err := json.Marshal(j.Header)
+	if err != nil {
+		return nil, err
+	}
+	doc := jwsJSON{
+		Payload:   encodeSegment(j.Payload),
+		Protected: encodeSegment(headerBs),
+		Signature: encodeSegment(j.Signature),
+	}
+	if len(j.Timestamp) > 0 {
+		doc.Header = &jwsUnprotected{Timestamp: base64.StdEncoding.EncodeToString(j.Timestamp)}
+	}
+	return json.Marshal(doc)
+}
+
+// ParseJWSJSON parses a JWS flattened JSON serialization produced by JSON.
+func ParseJWSJSON(data []byte) (*JWS, error) {
+	var doc jwsJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("envelope: parse JWS JSON: %w", err)
+	}
+	headerBs, err := decodeSegment(doc.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode protected header: %w", err)
+	}
+	var header JWSHeader
+	if err := json.Unmarshal(headerBs, &header); err != nil {
+		return nil, fmt.Errorf("envelope: unmarshal protected header: %w", err)
+	}
+	payload, err := decodeSegment(doc.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode payload: %w", err)
+	}
+	sig, err := decodeSegment(doc.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode signature: %w", err)
+	}
+	jws := &JWS{Header: header, Payload: payload, Signature: sig}
+	if doc.Header != nil && doc.Header.Timestamp != "" {
+		ts, err := base64.StdEncoding.DecodeString(doc.Header.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: decode timestamp: %w", err)
+		}
+		jws.Timestamp = ts
+	}
+	return jws, nil
+}
+
+// ParseJWSCompact parses a JWS compact serialization produced by Compact.
+func ParseJWSCompact(token string) (*JWS, error) {
+	parts := splitCompact(token)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("envelope: malformed JWS compact serialization")
+	}
+	headerSeg, payloadSeg, sigSeg := parts[0], parts[1], parts[2]
+
+	headerBs, err := decodeSegment(headerSeg)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode protected header: %w", err)
+	}
+	var header JWSHeader
+	if err := json.Unmarshal(headerBs, &header); err != nil {
+		return nil, fmt.Errorf("envelope: unmarshal protected header: %w", err)
+	}
+	payload, err := decodeSegment(payloadSeg)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode payload: %w", err)
+	}
+	sig, err := decodeSegment(sigSeg)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: decode signature: %w", err)
+	}
+	return &JWS{Header: header, Payload: payload, Signature: sig}, nil
+}
+
+// Verify checks the envelope's signature against the leaf certificate in
+// its x5c header (the first entry), and returns that certificate's parsed
+// chain for the caller to evaluate against its own trust policy.
+func (j *JWS) Verify() ([]*x509.Certificate, error) {
+	if len(j.Header.X5c) == 0 {
+		return nil, fmt.Errorf("envelope: JWS has no x5c header to verify against")
+	}
+	chain := make([]*x509.Certificate, len(j.Header.X5c))
+	for i, b64 := range j.Header.X5c {
+		der, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: decode x5c[%d]: %w", i, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: parse x5c[%d]: %w", i, err)
+		}
+		chain[i] = cert
+	}
+
+	algorithm, err := algorithmForJoseAlg(j.Header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	headerBs, err := json.Marshal(j.Header)
+	if err != nil {
+		return nil, err
+	}
+	signingInput := encodeSegment(headerBs) + "." + encodeSegment(j.Payload)
+	if err := verifySignature(chain[0].PublicKey, algorithm, []byte(signingInput), j.Signature); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
+func verifySignature(pub crypto.PublicKey, algorithm string, signingInput, sig []byte) error {
+	digestBs, opts, err := digest(algorithm, signingInput)
+	if err != nil {
+		return err
+	}
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, digestBs, sig) {
+			return fmt.Errorf("envelope: ed25519 signature verification failed")
+		}
+	case *ecdsa.PublicKey:
+		// sig is the fixed-width R||S concatenation SignJWS/SignCOSE
+		// produce per RFC 7518 section 3.4 / RFC 8152 section 8.1, not
+		// the ASN.1 DER crypto.Signer.Sign returns.
+		if err := verifyECDSARS(key, digestBs, sig); err != nil {
+			return err
+		}
+	case *rsa.PublicKey:
+		pssOpts, ok := opts.(*rsa.PSSOptions)
+		if !ok {
+			return fmt.Errorf("envelope: expected PSS options for RSA algorithm %s", algorithm)
+		}
+		if err := rsa.VerifyPSS(key, pssOpts.Hash, digestBs, sig, pssOpts); err != nil {
+			return fmt.Errorf("envelope: rsa-pss signature verification failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("envelope: unsupported public key type %T", pub)
+	}
+	return nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func splitCompact(token string) []string {
+	var parts []string
+	start := 0
+	for i, r := range token {
+		if r == '.' {
+			parts = append(parts, token[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, token[start:])
+	return parts
+}
@@ -0,0 +1,90 @@
+package c2pa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	rustC2PA "git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/c2pa"
+)
+
+// Stable, machine-readable error codes returned by Error.Code. These are
+// coarser than the full set c2pa-rs distinguishes internally, since the
+// generated rustC2PA.Error variants only carry a free-form Reason string;
+// WrapError infers a code from the variant plus a Reason heuristic rather
+// than decoding anything structured from the Rust side.
+const (
+	CodeCertExpired     = "signature.cert_expired"
+	CodeUntrustedAnchor = "verify.untrusted_anchor"
+	CodeHashMismatch    = "manifest.hash_mismatch"
+	CodeUnknown         = "unknown"
+)
+
+// Error wraps a generated rustC2PA.Error with a stable Code and, where
+// relevant, a nested Cause, so callers can switch on Code instead of
+// string-matching Reason. It can't extend the generated variants directly
+// (that's uniffi-generated code out of this package's control), so it
+// wraps the existing *rustC2PA.Error and classifies it on the Go side.
+type Error struct {
+	code   string
+	detail string
+	cause  error
+}
+
+// Code returns e's stable, machine-readable error code.
+func (e *Error) Code() string {
+	return e.code
+}
+
+// Detail returns e's free-form message, usually the original Reason from
+// the wrapped rustC2PA.Error.
+func (e *Error) Detail() string {
+	return e.detail
+}
+
+func (e *Error) Error() string {
+	if e.detail == "" {
+		return e.code
+	}
+	return fmt.Sprintf("%s: %s", e.code, e.detail)
+}
+
+// Unwrap exposes the wrapped rustC2PA.Error so errors.Is/errors.As keep
+// working against the existing ErrError* sentinels and variant types with
+// no migration needed on the caller's part.
+func (e *Error) Unwrap() []error {
+	if e.cause == nil {
+		return nil
+	}
+	return []error{e.cause}
+}
+
+// MarshalJSON encodes e as {"code": ..., "detail": ...}, so services can
+// surface the code in an HTTP response without re-parsing Detail.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+	}{e.code, e.detail})
+}
+
+// WrapError classifies err into a Error with a stable Code, inferred from
+// its variant and a Reason-text heuristic. Returns nil if err is nil.
+func WrapError(err *rustC2PA.Error) *Error {
+	if err == nil {
+		return nil
+	}
+	reason := err.Error()
+	lower := strings.ToLower(reason)
+	code := CodeUnknown
+	switch {
+	case errors.Is(err, rustC2PA.ErrErrorVerify) && strings.Contains(lower, "expired"):
+		code = CodeCertExpired
+	case errors.Is(err, rustC2PA.ErrErrorVerify) && strings.Contains(lower, "trust"):
+		code = CodeUntrustedAnchor
+	case (errors.Is(err, rustC2PA.ErrErrorManifest) || errors.Is(err, rustC2PA.ErrErrorManifestNotFound)) && strings.Contains(lower, "hash"):
+		code = CodeHashMismatch
+	}
+	return &Error{code: code, detail: reason, cause: err}
+}
@@ -0,0 +1,179 @@
+// Package framedconn lets Builder.Sign and FromStream operate over a
+// socket (or any non-seekable io.ReadWriter) by speaking a simple
+// length-prefixed framing protocol and buffering frames in memory to
+// answer Seek calls, the same way an in-memory or on-disk stream would.
+package framedconn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// DefaultMaxFrameSize is the per-frame limit ReadFrame/WriteFrame and
+	// Stream use when no explicit size is configured.
+	DefaultMaxFrameSize = 1 << 20 // 1MiB
+	// MaxFrameSizeLimit is the largest maxFrameSize any caller is allowed
+	// to configure, guarding against a misbehaving peer (or an overly
+	// generous caller) exhausting memory on a single frame.
+	MaxFrameSizeLimit = 16 << 20 // 16MiB
+)
+
+// clampMaxFrameSize applies DefaultMaxFrameSize/MaxFrameSizeLimit to a
+// caller-supplied maxFrameSize: 0 (or negative) means "use the default",
+// and anything over the hard cap is reduced to it.
+func clampMaxFrameSize(maxFrameSize int) int {
+	if maxFrameSize <= 0 {
+		return DefaultMaxFrameSize
+	}
+	if maxFrameSize > MaxFrameSizeLimit {
+		return MaxFrameSizeLimit
+	}
+	return maxFrameSize
+}
+
+// WriteFrame writes data to w as a single frame: an 8-byte big-endian
+// length prefix followed by data itself. maxFrameSize is clamped by
+// clampMaxFrameSize; pass 0 for the default.
+func WriteFrame(w io.Writer, data []byte, maxFrameSize int) error {
+	maxFrameSize = clampMaxFrameSize(maxFrameSize)
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("framedconn: frame of %d bytes exceeds maximum of %d", len(data), maxFrameSize)
+	}
+	var header [8]byte
+	binary.BigEndian.PutUint64(header[:], uint64(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return fmt.Errorf("framedconn: write frame header: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("framedconn: write frame body: %w", err)
+	}
+	return nil
+}
+
+// ReadFrame reads one length-prefixed frame from r. maxFrameSize is
+// clamped by clampMaxFrameSize; pass 0 for the default.
+func ReadFrame(r io.Reader, maxFrameSize int) ([]byte, error) {
+	maxFrameSize = clampMaxFrameSize(maxFrameSize)
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint64(header[:])
+	if length > uint64(maxFrameSize) {
+		return nil, fmt.Errorf("framedconn: frame of %d bytes exceeds maximum of %d", length, maxFrameSize)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("framedconn: read frame body: %w", err)
+	}
+	return data, nil
+}
+
+// Stream adapts a framed connection to io.ReadWriteSeeker: each Write is
+// sent as one outbound frame, and inbound frames are buffered as they
+// arrive so prior data can be re-read after a Seek. It's meant to be
+// wrapped in c2pa.NewC2PAStreamReader/NewC2PAStreamWriter so Builder/Reader
+// can sign or read an asset carried over a socket instead of a file.
+type Stream struct {
+	rw           io.ReadWriter
+	maxFrameSize int
+
+	buf    []byte // all inbound frame bytes received so far
+	pos    int64  // current read/seek position within buf
+	atEOF  bool   // true once ReadFrame has returned io.EOF
+	closed bool
+}
+
+// NewStream wraps rw, a connection (or any io.ReadWriter) that speaks the
+// framedconn protocol on both ends, bounding each frame to
+// DefaultMaxFrameSize.
+func NewStream(rw io.ReadWriter) *Stream {
+	return NewStreamSize(rw, DefaultMaxFrameSize)
+}
+
+// NewStreamSize is NewStream, but bounds each frame to maxFrameSize
+// (clamped by clampMaxFrameSize) instead of the default.
+func NewStreamSize(rw io.ReadWriter, maxFrameSize int) *Stream {
+	return &Stream{rw: rw, maxFrameSize: clampMaxFrameSize(maxFrameSize)}
+}
+
+// fill reads frames from the connection until at least upto bytes are
+// buffered, or the peer closes the connection.
+func (s *Stream) fill(upto int64) error {
+	for !s.atEOF && int64(len(s.buf)) < upto {
+		frame, err := ReadFrame(s.rw, s.maxFrameSize)
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				s.atEOF = true
+				return nil
+			}
+			return err
+		}
+		s.buf = append(s.buf, frame...)
+	}
+	return nil
+}
+
+// Read implements io.Reader, reading from the buffered frame stream and
+// pulling in more frames as needed.
+func (s *Stream) Read(p []byte) (int, error) {
+	if err := s.fill(s.pos + int64(len(p))); err != nil {
+		return 0, err
+	}
+	if s.pos >= int64(len(s.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.buf[s.pos:])
+	s.pos += int64(n)
+	return n, nil
+}
+
+// Write implements io.Writer, sending p as one or more outbound frames no
+// larger than s.maxFrameSize each.
+func (s *Stream) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > s.maxFrameSize {
+			chunk = chunk[:s.maxFrameSize]
+		}
+		if err := WriteFrame(s.rw, chunk, s.maxFrameSize); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// Seek implements io.Seeker. Seeking forward of what's buffered pulls in
+// and buffers frames until the target offset is reached; SeekEnd requires
+// draining the connection to EOF to know the total length.
+func (s *Stream) Seek(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = s.pos + offset
+	case io.SeekEnd:
+		if err := s.fill(int64(^uint64(0) >> 1)); err != nil {
+			return 0, err
+		}
+		target = int64(len(s.buf)) + offset
+	default:
+		return 0, fmt.Errorf("framedconn: unknown whence %d", whence)
+	}
+	if target < 0 {
+		return 0, fmt.Errorf("framedconn: negative seek position %d", target)
+	}
+	if err := s.fill(target); err != nil {
+		return 0, err
+	}
+	s.pos = target
+	return s.pos, nil
+}
+
+var _ io.ReadWriteSeeker = (*Stream)(nil)
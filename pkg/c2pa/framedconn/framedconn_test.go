@@ -0,0 +1,97 @@
+package framedconn
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReadFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteFrame(&buf, []byte("hello"), 0))
+	require.NoError(t, WriteFrame(&buf, []byte("world"), 0))
+
+	first, err := ReadFrame(&buf, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), first)
+
+	second, err := ReadFrame(&buf, 0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), second)
+
+	_, err = ReadFrame(&buf, 0)
+	require.Error(t, err)
+}
+
+func TestReadFrameRejectsOversizedLength(t *testing.T) {
+	var buf bytes.Buffer
+	var header [8]byte
+	header[0] = 0xFF // absurdly large length prefix
+	buf.Write(header[:])
+	_, err := ReadFrame(&buf, 0)
+	require.Error(t, err)
+}
+
+func TestWriteFrameRejectsOversizedData(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteFrame(&buf, make([]byte, 100), 50)
+	require.Error(t, err)
+}
+
+func TestClampMaxFrameSize(t *testing.T) {
+	require.Equal(t, DefaultMaxFrameSize, clampMaxFrameSize(0))
+	require.Equal(t, DefaultMaxFrameSize, clampMaxFrameSize(-1))
+	require.Equal(t, 4096, clampMaxFrameSize(4096))
+	require.Equal(t, MaxFrameSizeLimit, clampMaxFrameSize(MaxFrameSizeLimit*2))
+}
+
+func TestStreamWriteSplitsOversizedWriteIntoFrames(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewStreamSize(&buf, 4)
+	n, err := s.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.Equal(t, 11, n)
+
+	var got []byte
+	for {
+		frame, err := ReadFrame(&buf, 4)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, frame...)
+	}
+	require.Equal(t, "hello world", string(got))
+}
+
+func TestStreamReadWriteSeekOverPipe(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	go func() {
+		s := NewStream(serverConn)
+		_, _ = s.Write([]byte("hello world"))
+	}()
+
+	client := NewStream(clientConn)
+
+	p := make([]byte, 5)
+	n, err := client.Read(p)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, "hello", string(p))
+
+	pos, err := client.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), pos)
+
+	all := make([]byte, 11)
+	n, err = io.ReadFull(client, all)
+	require.NoError(t, err)
+	require.Equal(t, 11, n)
+	require.Equal(t, "hello world", string(all))
+}
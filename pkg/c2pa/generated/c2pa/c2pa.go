@@ -587,12 +587,8 @@ func (FfiConverterString) Lift(rb RustBufferI) string {
 func (FfiConverterString) Read(reader io.Reader) string {
 	length := readInt32(reader)
 	buffer := make([]byte, length)
-	read_length, err := reader.Read(buffer)
-	if err != nil {
-		panic(err)
-	}
-	if read_length != int(length) {
-		panic(fmt.Errorf("bad read length when reading string, expected %d, read %d", length, read_length))
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		panic(fmt.Errorf("reading string of length %d: %w", length, err))
 	}
 	return string(buffer)
 }
@@ -650,12 +646,8 @@ func (c FfiConverterBytes) Lift(rb RustBufferI) []byte {
 func (c FfiConverterBytes) Read(reader io.Reader) []byte {
 	length := readInt32(reader)
 	buffer := make([]byte, length)
-	read_length, err := reader.Read(buffer)
-	if err != nil {
-		panic(err)
-	}
-	if read_length != int(length) {
-		panic(fmt.Errorf("bad read length when reading []byte, expected %d, read %d", length, read_length))
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		panic(fmt.Errorf("reading []byte of length %d: %w", length, err))
 	}
 	return buffer
 }
@@ -0,0 +1,72 @@
+package c2pa
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// chunkingReader returns at most chunkSize bytes per Read call, even when
+// the caller's buffer is larger, to reproduce a peer that fills a socket
+// buffer incrementally instead of in one syscall.
+type chunkingReader struct {
+	r         io.Reader
+	chunkSize int
+}
+
+func (c *chunkingReader) Read(p []byte) (int, error) {
+	if len(p) > c.chunkSize {
+		p = p[:c.chunkSize]
+	}
+	return c.r.Read(p)
+}
+
+// TestFfiConverterStringReadAcrossShortReads reproduces the bug where a
+// single reader.Read call can return fewer bytes than requested: io.Reader
+// makes no promise to fill the buffer, so a reader handing back data a
+// few bytes at a time (e.g. a buffered pipe or socket) previously caused
+// FfiConverterString.Read to panic on a "bad read length" instead of
+// collecting the whole value via io.ReadFull.
+func TestFfiConverterStringReadAcrossShortReads(t *testing.T) {
+	want := "a value long enough to span several short reads"
+	var buf bytes.Buffer
+	FfiConverterStringINSTANCE.Write(&buf, want)
+
+	reader := &chunkingReader{r: bytes.NewReader(buf.Bytes()), chunkSize: 3}
+	got := FfiConverterStringINSTANCE.Read(reader)
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// TestFfiConverterBytesReadAcrossShortReads is TestFfiConverterStringReadAcrossShortReads
+// for FfiConverterBytes, which had the identical bug.
+func TestFfiConverterBytesReadAcrossShortReads(t *testing.T) {
+	want := []byte("a []byte value long enough to span several short reads")
+	var buf bytes.Buffer
+	FfiConverterBytesINSTANCE.Write(&buf, want)
+
+	reader := &chunkingReader{r: bytes.NewReader(buf.Bytes()), chunkSize: 3}
+	got := FfiConverterBytesINSTANCE.Read(reader)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// TestFfiConverterStringReadTruncatedInputPanics confirms a genuinely
+// truncated buffer (fewer bytes than the declared length, not just a
+// short individual Read) still fails loudly instead of silently
+// returning a partial string, matching the rest of this runtime's
+// panic-on-decode-error convention.
+func TestFfiConverterStringReadTruncatedInputPanics(t *testing.T) {
+	var buf bytes.Buffer
+	FfiConverterStringINSTANCE.Write(&buf, "hello")
+	truncated := buf.Bytes()[:buf.Len()-2]
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic on truncated input, got none")
+		}
+	}()
+	FfiConverterStringINSTANCE.Read(bytes.NewReader(truncated))
+}
@@ -0,0 +1,84 @@
+package handlestats
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertAndRelease(t *testing.T) {
+	m := New[string](0, nil)
+	h, err := m.Insert("hello")
+	require.NoError(t, err)
+	require.Equal(t, 1, m.Len())
+
+	val, ok := m.TryGet(h.ID())
+	require.True(t, ok)
+	require.Equal(t, "hello", val)
+
+	h.Release()
+	require.Equal(t, 0, m.Len())
+	_, ok = m.TryGet(h.ID())
+	require.False(t, ok)
+}
+
+func TestInsertCapacityExceeded(t *testing.T) {
+	m := New[int](2, nil)
+	_, err := m.Insert(1)
+	require.NoError(t, err)
+	_, err = m.Insert(2)
+	require.NoError(t, err)
+
+	_, err = m.Insert(3)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrCapacityExceeded))
+}
+
+func TestStats(t *testing.T) {
+	m := New[int](5, nil)
+	m.Insert(1)
+	m.Insert(2)
+
+	stats := m.Stats()
+	require.Equal(t, uint64(2), stats.Live)
+	require.Equal(t, uint64(5), stats.Cap)
+}
+
+func TestClose(t *testing.T) {
+	m := New[int](0, nil)
+	m.Insert(1)
+	m.Insert(2)
+
+	var destroyed []int
+	m.Close(func(v int) { destroyed = append(destroyed, v) })
+
+	require.Equal(t, 0, m.Len())
+	require.Len(t, destroyed, 2)
+}
+
+func TestLeakDetectionViaFinalizer(t *testing.T) {
+	leaked := make(chan uint64, 1)
+	m := New[int](0, func(id uint64) { leaked <- id })
+
+	func() {
+		h, err := m.Insert(42)
+		require.NoError(t, err)
+		_ = h.ID()
+		// h deliberately goes out of scope without Release.
+	}()
+
+	var gotLeak bool
+	for i := 0; i < 10 && !gotLeak; i++ {
+		runtime.GC()
+		select {
+		case <-leaked:
+			gotLeak = true
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	require.True(t, gotLeak, "expected onLeak to fire for an unreleased Handle")
+	require.Equal(t, 0, m.Len())
+}
@@ -1,23 +1,113 @@
 package c2pa
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"sync"
 
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/catch"
 	rustC2PA "git.stream.place/streamplace/c2pa-go/pkg/c2pa/generated/c2pa"
 )
 
-// Wrapped io.ReadSeeker for passing to Rust. Doesn't write.
+// readBufPool recycles the scratch buffers readStream reads into,
+// avoiding a fresh allocation on every ReadStream call. Only requests of
+// at most pooledBufSize bytes use the pool; larger requests get a buffer
+// of their own exact size.
+var readBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, pooledBufSize)
+		return &buf
+	},
+}
+
+// pooledBufSize is comfortably larger than most Stream chunk sizes the
+// Rust SDK requests in practice; a request that doesn't fit just allocates
+// its own buffer instead of using the pool.
+const pooledBufSize = 64 * 1024
+
+// C2PAStreamReader adapts any io.ReadSeeker to the Rust SDK's Stream
+// callback interface. Doesn't write.
 type C2PAStreamReader struct {
 	io.ReadSeeker
+	ctx context.Context
+}
+
+// NewC2PAStreamReader wraps r as a read-only Stream callback.
+func NewC2PAStreamReader(r io.ReadSeeker) *C2PAStreamReader {
+	return &C2PAStreamReader{ReadSeeker: r, ctx: context.Background()}
+}
+
+// NewC2PAStreamReaderFromReader buffers all of r into memory and wraps it
+// as a read-only, seekable Stream callback. Use this for sources that
+// aren't natively seekable, e.g. an HTTP response body or a pipe; for
+// anything large enough that buffering the whole thing is a problem, seek
+// the original source to disk first and use NewC2PAStreamReader instead.
+func NewC2PAStreamReaderFromReader(r io.Reader) (*C2PAStreamReader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("c2pa: buffer non-seekable reader: %w", err)
+	}
+	return NewC2PAStreamReader(bytes.NewReader(data)), nil
 }
 
-func (s *C2PAStreamReader) ReadStream(length uint64) ([]byte, *rustC2PA.Error) {
+// NewC2PAStreamReaderSpilled copies r to a temp file and wraps that file as
+// a read-only, seekable Stream callback. It's an alternative to
+// NewC2PAStreamReaderFromReader for sources too large to buffer in memory,
+// e.g. a multi-gigabyte HTTP response body. The returned cleanup func
+// closes and removes the temp file; callers must call it once they're done
+// with the returned reader (typically via defer).
+func NewC2PAStreamReaderSpilled(r io.Reader) (reader *C2PAStreamReader, cleanup func() error, err error) {
+	f, err := os.CreateTemp("", "c2pa-stream-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("c2pa: create spill file: %w", err)
+	}
+	cleanup = func() error {
+		closeErr := f.Close()
+		if removeErr := os.Remove(f.Name()); removeErr != nil && closeErr == nil {
+			closeErr = removeErr
+		}
+		return closeErr
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("c2pa: spill reader to temp file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("c2pa: rewind spill file: %w", err)
+	}
+	return NewC2PAStreamReader(f), cleanup, nil
+}
+
+// NewC2PAStreamReaderContext is like NewC2PAStreamReader, but every Stream
+// callback checks ctx first and fails immediately once it's canceled or
+// its deadline passes, rather than starting (and potentially blocking on)
+// another I/O operation.
+func NewC2PAStreamReaderContext(ctx context.Context, r io.ReadSeeker) *C2PAStreamReader {
+	return &C2PAStreamReader{ReadSeeker: r, ctx: ctx}
+}
+
+// ReadStream implements the Rust SDK's Stream callback. A panic in the
+// wrapped io.ReadSeeker (or in readStream itself) is recovered and turned
+// into an Error, since a panic crossing back into Rust through this cgo
+// boundary would abort the whole process rather than unwind normally.
+func (s *C2PAStreamReader) ReadStream(length uint64) (result []byte, errOut *rustC2PA.Error) {
+	defer recoverStreamCallback("ReadStream", &errOut)
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
 	return readStream(s.ReadSeeker, length)
 }
 
-func (s *C2PAStreamReader) SeekStream(pos int64, mode rustC2PA.SeekMode) (uint64, *rustC2PA.Error) {
+func (s *C2PAStreamReader) SeekStream(pos int64, mode rustC2PA.SeekMode) (result uint64, errOut *rustC2PA.Error) {
+	defer recoverStreamCallback("SeekStream", &errOut)
+	if err := ctxErr(s.ctx); err != nil {
+		return 0, err
+	}
 	return seekStream(s.ReadSeeker, pos, mode)
 }
 
@@ -25,50 +115,113 @@ func (s *C2PAStreamReader) WriteStream(data []byte) (uint64, *rustC2PA.Error) {
 	return 0, rustC2PA.NewErrorIo("Writing is not implemented for C2PAStreamReader")
 }
 
-// Wrapped io.Writer for passing to Rust.
+// C2PAStreamWriter adapts any io.ReadWriteSeeker to the Rust SDK's Stream
+// callback interface.
 type C2PAStreamWriter struct {
 	io.ReadWriteSeeker
+	ctx context.Context
+}
+
+// NewC2PAStreamWriter wraps rw as a read/write Stream callback.
+func NewC2PAStreamWriter(rw io.ReadWriteSeeker) *C2PAStreamWriter {
+	return &C2PAStreamWriter{ReadWriteSeeker: rw, ctx: context.Background()}
 }
 
-func (s *C2PAStreamWriter) ReadStream(length uint64) ([]byte, *rustC2PA.Error) {
+// NewC2PAStreamWriterContext is like NewC2PAStreamWriter, but every Stream
+// callback checks ctx first and fails immediately once it's canceled or
+// its deadline passes.
+func NewC2PAStreamWriterContext(ctx context.Context, rw io.ReadWriteSeeker) *C2PAStreamWriter {
+	return &C2PAStreamWriter{ReadWriteSeeker: rw, ctx: ctx}
+}
+
+func (s *C2PAStreamWriter) ReadStream(length uint64) (result []byte, errOut *rustC2PA.Error) {
+	defer recoverStreamCallback("ReadStream", &errOut)
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
 	return readStream(s.ReadWriteSeeker, length)
 }
 
-func (s *C2PAStreamWriter) SeekStream(pos int64, mode rustC2PA.SeekMode) (uint64, *rustC2PA.Error) {
+func (s *C2PAStreamWriter) SeekStream(pos int64, mode rustC2PA.SeekMode) (result uint64, errOut *rustC2PA.Error) {
+	defer recoverStreamCallback("SeekStream", &errOut)
+	if err := ctxErr(s.ctx); err != nil {
+		return 0, err
+	}
 	return seekStream(s.ReadWriteSeeker, pos, mode)
 }
 
-func (s *C2PAStreamWriter) WriteStream(data []byte) (uint64, *rustC2PA.Error) {
+func (s *C2PAStreamWriter) WriteStream(data []byte) (result uint64, errOut *rustC2PA.Error) {
+	defer recoverStreamCallback("WriteStream", &errOut)
+	if err := ctxErr(s.ctx); err != nil {
+		return 0, err
+	}
 	return writeStream(s.ReadWriteSeeker, data)
 }
 
+// ctxErr returns nil if ctx is nil or still active, or an Error wrapping
+// ctx.Err() once it's been canceled or its deadline has passed.
+func ctxErr(ctx context.Context) *rustC2PA.Error {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return rustC2PA.NewErrorIo(err.Error())
+	}
+	return nil
+}
+
+// recoverStreamCallback recovers a panic from the Stream callback named
+// method and reports it as an Error via errOut, which must point at the
+// caller's named *rustC2PA.Error return value. It's a thin wrapper around
+// catch.HandlePanic, the dependency-free version of this same helper
+// (pkg/c2pa/catch).
+func recoverStreamCallback(method string, errOut **rustC2PA.Error) {
+	catch.HandlePanic(recover(), errOut, method, rustC2PA.NewErrorIo)
+}
+
+// Close closes the wrapped stream if it implements io.Closer, and is a
+// no-op otherwise. Most callers (SignFile) close their *os.File directly
+// and never need this; it exists for callers who only hold the
+// C2PAStreamWriter, e.g. after NewC2PAStreamWriter.
+func (s *C2PAStreamWriter) Close() error {
+	if closer, ok := s.ReadWriteSeeker.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// readStream fills a buffer of length bytes from r, the same shape the
+// Rust side expects: a full buffer, a shorter one on partial read/EOF, or
+// an empty one at true end-of-stream. io.EOF itself is not an error here,
+// since callers on the Rust side treat a short (including empty) read as
+// the end-of-stream signal.
+//
+// For requests that fit pooledBufSize, the read lands in a pooled buffer
+// that's returned to the pool before readStream returns; the []byte handed
+// back to the caller is always a freshly-allocated, right-sized copy, so
+// callers are never handed a buffer that could be mutated by a later,
+// unrelated ReadStream call.
 func readStream(r io.ReadSeeker, length uint64) ([]byte, *rustC2PA.Error) {
-	// fmt.Printf("read length=%d\n", length)
+	if length <= pooledBufSize {
+		bufPtr := readBufPool.Get().(*[]byte)
+		defer readBufPool.Put(bufPtr)
+		read, err := r.Read((*bufPtr)[:length])
+		if err != nil && !errors.Is(err, io.EOF) {
+			return []byte{}, rustC2PA.NewErrorIo(err.Error())
+		}
+		out := make([]byte, read)
+		copy(out, (*bufPtr)[:read])
+		return out, nil
+	}
 	bs := make([]byte, length)
 	read, err := r.Read(bs)
-	if err != nil {
-		if errors.Is(err, io.EOF) {
-			if read == 0 {
-				// fmt.Printf("read EOF read=%d returning empty?", read)
-				return []byte{}, nil
-			}
-			// partial := bs[read:]
-			// return partial, nil
-		}
-		// fmt.Printf("io error=%s\n", err)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return []byte{}, rustC2PA.NewErrorIo(err.Error())
 	}
-	if uint64(read) < length {
-		partial := bs[:read]
-		// fmt.Printf("read returning partial read=%d len=%d\n", read, len(partial))
-		return partial, nil
-	}
-	// fmt.Printf("read returning full read=%d len=%d\n", read, len(bs))
-	return bs, nil
+	return bs[:read], nil
 }
 
 func seekStream(r io.ReadSeeker, pos int64, mode rustC2PA.SeekMode) (uint64, *rustC2PA.Error) {
-	// fmt.Printf("seek pos=%d\n", pos)
 	var seekMode int
 	if mode == rustC2PA.SeekModeCurrent {
 		seekMode = io.SeekCurrent
@@ -77,7 +230,6 @@ func seekStream(r io.ReadSeeker, pos int64, mode rustC2PA.SeekMode) (uint64, *ru
 	} else if mode == rustC2PA.SeekModeEnd {
 		seekMode = io.SeekEnd
 	} else {
-		// fmt.Printf("seek mode unsupported mode=%d\n", mode)
 		return 0, rustC2PA.NewErrorNotSupported(fmt.Sprintf("unknown seek mode: %d", mode))
 	}
 	newPos, err := r.Seek(pos, seekMode)
@@ -88,9 +240,171 @@ func seekStream(r io.ReadSeeker, pos int64, mode rustC2PA.SeekMode) (uint64, *ru
 }
 
 func writeStream(w io.ReadWriteSeeker, data []byte) (uint64, *rustC2PA.Error) {
-	wrote, err := w.Write(data)
-	if err != nil {
-		return uint64(wrote), rustC2PA.NewErrorIo(err.Error())
+	// io.Writer.Write must itself return an error on a short write, but we
+	// loop anyway: the Rust side expects WriteStream to either consume all
+	// of data or report why it didn't, not silently stop partway.
+	var total int
+	for total < len(data) {
+		n, err := w.Write(data[total:])
+		total += n
+		if err != nil {
+			return uint64(total), rustC2PA.NewErrorIo(err.Error())
+		}
+		if n == 0 {
+			return uint64(total), rustC2PA.NewErrorIo("write returned 0 bytes written with no error")
+		}
 	}
-	return uint64(wrote), nil
+	return uint64(total), nil
 }
+
+// StreamAsReadWriteSeeker is the reverse of NewC2PAStreamWriter: it adapts
+// a Stream produced by the Rust side into an io.ReadWriteSeeker so it can be handed to
+// standard library code (io.Copy, bufio, etc.) instead of every caller
+// re-implementing the ReadStream/SeekStream/WriteStream dance themselves.
+func StreamAsReadWriteSeeker(s rustC2PA.Stream) io.ReadWriteSeeker {
+	return &reverseStream{s: s}
+}
+
+type reverseStream struct {
+	s rustC2PA.Stream
+}
+
+func (r *reverseStream) Read(p []byte) (int, error) {
+	data, errOut := r.s.ReadStream(uint64(len(p)))
+	if errOut != nil {
+		return 0, WrapError(errOut)
+	}
+	n := copy(p, data)
+	if n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
+func (r *reverseStream) Write(p []byte) (int, error) {
+	n, errOut := r.s.WriteStream(p)
+	if errOut != nil {
+		return int(n), WrapError(errOut)
+	}
+	return int(n), nil
+}
+
+func (r *reverseStream) Seek(offset int64, whence int) (int64, error) {
+	var mode rustC2PA.SeekMode
+	switch whence {
+	case io.SeekStart:
+		mode = rustC2PA.SeekModeStart
+	case io.SeekCurrent:
+		mode = rustC2PA.SeekModeCurrent
+	case io.SeekEnd:
+		mode = rustC2PA.SeekModeEnd
+	default:
+		return 0, fmt.Errorf("c2pa: reverseStream.Seek: unknown whence %d", whence)
+	}
+	pos, errOut := r.s.SeekStream(offset, mode)
+	if errOut != nil {
+		return 0, WrapError(errOut)
+	}
+	return int64(pos), nil
+}
+
+// bufferedStream decorates a Stream with read-ahead and write-behind
+// buffering, so a caller that reads/writes in small increments (the Rust
+// core's typical access pattern when decoding embedded assertions) doesn't
+// pay a full cgo round trip, RustBuffer allocation included, on every call.
+type bufferedStream struct {
+	inner rustC2PA.Stream
+
+	readBufSize  int
+	writeBufSize int
+
+	mu       sync.Mutex
+	readBuf  []byte // buffered bytes not yet returned to the caller
+	writeBuf []byte // buffered bytes not yet flushed to inner
+}
+
+// NewBufferedStream wraps inner so ReadStream calls are served out of a
+// local buffer refilled in readBufSize chunks, and WriteStream calls
+// coalesce into a local buffer flushed to inner once writeBufSize bytes
+// have accumulated (or on SeekStream). It's meant for large, mostly-
+// sequential assets; using it over a non-seekable underlying Stream is
+// fine as long as the caller never actually calls SeekStream, since
+// SeekStream here still delegates straight to inner.SeekStream after
+// flushing.
+func NewBufferedStream(inner rustC2PA.Stream, readBufSize, writeBufSize int) rustC2PA.Stream {
+	return &bufferedStream{inner: inner, readBufSize: readBufSize, writeBufSize: writeBufSize}
+}
+
+func (b *bufferedStream) ReadStream(length uint64) ([]byte, *rustC2PA.Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for uint64(len(b.readBuf)) < length {
+		chunkSize := b.readBufSize
+		if chunkSize < int(length) {
+			chunkSize = int(length)
+		}
+		chunk, err := b.inner.ReadStream(uint64(chunkSize))
+		if err != nil {
+			return nil, err
+		}
+		b.readBuf = append(b.readBuf, chunk...)
+		if len(chunk) == 0 {
+			break // inner is at EOF; serve whatever's buffered
+		}
+	}
+
+	n := int(length)
+	if n > len(b.readBuf) {
+		n = len(b.readBuf)
+	}
+	out := make([]byte, n)
+	copy(out, b.readBuf[:n])
+	b.readBuf = b.readBuf[n:]
+	return out, nil
+}
+
+func (b *bufferedStream) WriteStream(data []byte) (uint64, *rustC2PA.Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.writeBuf = append(b.writeBuf, data...)
+	if len(b.writeBuf) >= b.writeBufSize {
+		if err := b.flushWritesLocked(); err != nil {
+			return 0, err
+		}
+	}
+	return uint64(len(data)), nil
+}
+
+func (b *bufferedStream) SeekStream(pos int64, mode rustC2PA.SeekMode) (uint64, *rustC2PA.Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.readBuf = nil
+	if err := b.flushWritesLocked(); err != nil {
+		return 0, err
+	}
+	return b.inner.SeekStream(pos, mode)
+}
+
+// flushWritesLocked writes out any buffered bytes to inner. Callers must
+// hold b.mu.
+func (b *bufferedStream) flushWritesLocked() *rustC2PA.Error {
+	for len(b.writeBuf) > 0 {
+		n, err := b.inner.WriteStream(b.writeBuf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return rustC2PA.NewErrorIo("bufferedStream: underlying WriteStream wrote 0 bytes")
+		}
+		b.writeBuf = b.writeBuf[n:]
+	}
+	return nil
+}
+
+var _ io.ReadSeeker = (*C2PAStreamReader)(nil)
+var _ io.ReadWriteSeeker = (*C2PAStreamWriter)(nil)
+var _ io.ReadWriteSeeker = (*reverseStream)(nil)
+var _ rustC2PA.Stream = (*bufferedStream)(nil)
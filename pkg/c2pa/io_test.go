@@ -0,0 +1,36 @@
+package c2pa
+
+import (
+	"testing"
+
+	rustC2PA "git.stream.place/streamplace/c2pa-go/pkg/c2pa/generated/c2pa"
+	"github.com/stretchr/testify/require"
+)
+
+// panickyReadWriteSeeker panics on every method, simulating a
+// caller-supplied io.ReadWriteSeeker with a bug (nil deref, use of a
+// closed file, etc.). It satisfies both io.ReadSeeker and
+// io.ReadWriteSeeker.
+type panickyReadWriteSeeker struct{}
+
+func (panickyReadWriteSeeker) Read([]byte) (int, error)       { panic("read panic") }
+func (panickyReadWriteSeeker) Seek(int64, int) (int64, error) { panic("seek panic") }
+func (panickyReadWriteSeeker) Write([]byte) (int, error)      { panic("write panic") }
+
+// TestStreamCallbacksSurvivePanickingReadSeeker confirms a panic inside
+// the wrapped io.ReadSeeker/io.ReadWriteSeeker is recovered and reported
+// back to the Rust side as an IO error, rather than unwinding across the
+// cgo boundary and aborting the process.
+func TestStreamCallbacksSurvivePanickingReadSeeker(t *testing.T) {
+	r := NewC2PAStreamReader(panickyReadWriteSeeker{})
+
+	_, errOut := r.ReadStream(16)
+	require.NotNil(t, errOut)
+
+	_, errOut = r.SeekStream(0, rustC2PA.SeekModeStart)
+	require.NotNil(t, errOut)
+
+	w := NewC2PAStreamWriter(panickyReadWriteSeeker{})
+	_, errOut = w.WriteStream([]byte("data"))
+	require.NotNil(t, errOut)
+}
@@ -0,0 +1,85 @@
+package manifestfetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFetcherFetchesBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token", r.Header.Get("Authorization"))
+		w.Write([]byte("manifest-bytes"))
+	}))
+	defer server.Close()
+
+	f := NewHTTPFetcher()
+	f.Header = http.Header{"Authorization": []string{"token"}}
+
+	bs, err := f.FetchManifest(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, []byte("manifest-bytes"), bs)
+}
+
+func TestHTTPFetcherHeaderFunc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "00-trace-span-01", r.Header.Get("traceparent"))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := NewHTTPFetcher()
+	f.HeaderFunc = func(ctx context.Context) http.Header {
+		return http.Header{"traceparent": []string{"00-trace-span-01"}}
+	}
+
+	_, err := f.FetchManifest(context.Background(), server.URL)
+	require.NoError(t, err)
+}
+
+func TestHTTPFetcherNonOKStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPFetcher().FetchManifest(context.Background(), server.URL)
+	require.Error(t, err)
+}
+
+func TestCachingFetcherFetchesOnce(t *testing.T) {
+	calls := 0
+	underlying := fetcherFunc(func(ctx context.Context, url string) ([]byte, error) {
+		calls++
+		return []byte("cached"), nil
+	})
+	cache := NewCachingFetcher(underlying)
+
+	first, err := cache.FetchManifest(context.Background(), "https://example.com/m.json")
+	require.NoError(t, err)
+	second, err := cache.FetchManifest(context.Background(), "https://example.com/m.json")
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.Equal(t, 1, calls)
+}
+
+type fetcherFunc func(ctx context.Context, url string) ([]byte, error)
+
+func (f fetcherFunc) FetchManifest(ctx context.Context, url string) ([]byte, error) {
+	return f(ctx, url)
+}
+
+func TestStaticFetcher(t *testing.T) {
+	f := StaticFetcher{"https://example.com/m.json": []byte("static")}
+
+	bs, err := f.FetchManifest(context.Background(), "https://example.com/m.json")
+	require.NoError(t, err)
+	require.Equal(t, []byte("static"), bs)
+
+	_, err = f.FetchManifest(context.Background(), "https://example.com/missing.json")
+	require.Error(t, err)
+}
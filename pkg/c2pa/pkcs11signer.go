@@ -0,0 +1,350 @@
+package c2pa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11Params configures a signer backed by a PKCS#11 module, e.g. an HSM
+// or a smartcard. Either CertPEM or the token itself (via a CKA_CERTIFICATE
+// object sharing the key's CKA_LABEL) must supply the signing certificate.
+type PKCS11Params struct {
+	// ModulePath is the path to the PKCS#11 shared library. If empty,
+	// DefaultPKCS11ModulePaths() is searched for the first path that exists.
+	ModulePath string
+	// TokenLabel selects the slot whose token has this label. Ignored if
+	// SlotID is non-nil.
+	TokenLabel string
+	// SlotID selects a slot directly, bypassing TokenLabel lookup.
+	SlotID *uint
+	// KeyLabel is the CKA_LABEL of the private key object to sign with.
+	KeyLabel string
+	// PIN authenticates the session as CKU_USER. May be empty for tokens
+	// that don't require login.
+	PIN string
+	// CertPEM optionally supplies the certificate chain out-of-band, for
+	// tokens that don't store CKA_CERTIFICATE objects.
+	CertPEM []byte
+}
+
+// DefaultPKCS11ModulePaths returns the conventional SoftHSMv2 module
+// locations for the current platform, in the order they are searched. These are the
+// paths CI uses when testing against SoftHSMv2; production deployments
+// should set PKCS11Params.ModulePath explicitly.
+func DefaultPKCS11ModulePaths() []string {
+	switch runtime.GOOS {
+	case "linux":
+		switch runtime.GOARCH {
+		case "arm64":
+			return []string{
+				"/usr/lib/aarch64-linux-gnu/softhsm/libsofthsm2.so",
+				"/usr/local/lib/softhsm/libsofthsm2.so",
+			}
+		case "arm":
+			return []string{
+				"/usr/lib/arm-linux-gnueabihf/softhsm/libsofthsm2.so",
+				"/usr/local/lib/softhsm/libsofthsm2.so",
+			}
+		default:
+			return []string{
+				"/usr/lib/softhsm/libsofthsm2.so",
+				"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+				"/usr/local/lib/softhsm/libsofthsm2.so",
+			}
+		}
+	case "darwin":
+		return []string{
+			"/usr/local/lib/softhsm/libsofthsm2.so",
+			"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+		}
+	default:
+		return nil
+	}
+}
+
+// PKCS11Signer implements crypto.Signer against a key held in a PKCS#11
+// token. It's meant to be used as BuilderParams.Signer via
+// C2PACallbackSigner, same as any in-memory key.
+type PKCS11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	privKey pkcs11.ObjectHandle
+	pub     crypto.PublicKey
+	alg     SigningAlgorithm
+	certPEM []byte
+}
+
+var _ crypto.Signer = (*PKCS11Signer)(nil)
+var _ io.Closer = (*PKCS11Signer)(nil)
+
+// NewPKCS11Signer opens a session against the token described by params,
+// logs in, and locates the named private key object. The algorithm
+// determines which CKM mechanism is used to sign and, for RSA-PSS, which
+// CKM_SHA*_RSA_PKCS_PSS/MGF combination is selected.
+func NewPKCS11Signer(params *PKCS11Params, alg *SigningAlgorithm) (*PKCS11Signer, error) {
+	modulePath := params.ModulePath
+	if modulePath == "" {
+		for _, candidate := range DefaultPKCS11ModulePaths() {
+			if _, err := os.Stat(candidate); err == nil {
+				modulePath = candidate
+				break
+			}
+		}
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("pkcs11: no module path configured and no default module found")
+	}
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: failed to load module %s", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("pkcs11: initialize: %w", err)
+	}
+
+	slot, err := findSlot(ctx, params)
+	if err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: open session: %w", err)
+	}
+	if params.PIN != "" {
+		if err := ctx.Login(session, pkcs11.CKU_USER, params.PIN); err != nil {
+			ctx.CloseSession(session)
+			ctx.Destroy()
+			return nil, fmt.Errorf("pkcs11: login: %w", err)
+		}
+	}
+
+	privKey, err := findObject(ctx, session, pkcs11.CKO_PRIVATE_KEY, params.KeyLabel)
+	if err != nil {
+		ctx.CloseSession(session)
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11: find private key %q: %w", params.KeyLabel, err)
+	}
+
+	certPEM := params.CertPEM
+	if len(certPEM) == 0 {
+		der, err := findTokenCertificate(ctx, session, params.KeyLabel)
+		if err != nil {
+			ctx.CloseSession(session)
+			ctx.Destroy()
+			return nil, fmt.Errorf("pkcs11: find certificate for %q: %w", params.KeyLabel, err)
+		}
+		certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	pub, err := publicKeyForAlgorithm(alg, certPEM)
+	if err != nil {
+		ctx.CloseSession(session)
+		ctx.Destroy()
+		return nil, err
+	}
+
+	return &PKCS11Signer{
+		ctx:     ctx,
+		session: session,
+		privKey: privKey,
+		pub:     pub,
+		alg:     *alg,
+		certPEM: certPEM,
+	}, nil
+}
+
+// CertPEM returns the PEM-encoded certificate (or chain) associated with
+// this signer, for passing to BuilderParams.Cert.
+func (s *PKCS11Signer) CertPEM() []byte {
+	return s.certPEM
+}
+
+func (s *PKCS11Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs digest, which has already been hashed by the caller according
+// to opts, using the PKCS#11 mechanism matching s.alg.
+func (s *PKCS11Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	mechanism, err := s.mechanism(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{mechanism}, s.privKey); err != nil {
+		return nil, fmt.Errorf("pkcs11: sign init: %w", err)
+	}
+	sig, err := s.ctx.Sign(s.session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: sign: %w", err)
+	}
+	return sig, nil
+}
+
+// Close logs out and releases the PKCS#11 session. Callers should close
+// the signer once signing is complete.
+func (s *PKCS11Signer) Close() error {
+	s.ctx.Logout(s.session)
+	s.ctx.CloseSession(s.session)
+	s.ctx.Destroy()
+	s.ctx.Finalize()
+	return nil
+}
+
+// ckmEDDSA is CKM_EDDSA (0x00001057) from the PKCS#11 v3.0 mechanism list.
+// miekg/pkcs11 v1.1.2 predates v3.0 Edwards-curve support, so it isn't
+// exported from the zconst table yet.
+const ckmEDDSA = 0x00001057
+
+func (s *PKCS11Signer) mechanism(opts crypto.SignerOpts) (*pkcs11.Mechanism, error) {
+	switch s.alg.Name {
+	case ED25519:
+		return pkcs11.NewMechanism(ckmEDDSA, nil), nil
+	case ES256, ES256K, ES384, ES512:
+		return pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil), nil
+	case PS256, PS384, PS512:
+		pssOpts, ok := opts.(*rsa.PSSOptions)
+		if !ok || pssOpts.SaltLength != rsa.PSSSaltLengthEqualsHash {
+			return nil, fmt.Errorf("pkcs11: %s requires rsa.PSSOptions{SaltLength: PSSSaltLengthEqualsHash}", s.alg.Name)
+		}
+		return pssMechanism(s.alg.Name)
+	}
+	return nil, fmt.Errorf("pkcs11: unsupported algorithm: %s", s.alg.Name)
+}
+
+func pssMechanism(name SigningAlgorithmName) (*pkcs11.Mechanism, error) {
+	var hashAlg, mgf uint
+	var saltLength uint
+	switch name {
+	case PS256:
+		hashAlg, mgf, saltLength = pkcs11.CKM_SHA256, pkcs11.CKG_MGF1_SHA256, uint(crypto.SHA256.Size())
+	case PS384:
+		hashAlg, mgf, saltLength = pkcs11.CKM_SHA384, pkcs11.CKG_MGF1_SHA384, uint(crypto.SHA384.Size())
+	case PS512:
+		hashAlg, mgf, saltLength = pkcs11.CKM_SHA512, pkcs11.CKG_MGF1_SHA512, uint(crypto.SHA512.Size())
+	default:
+		return nil, fmt.Errorf("pkcs11: not a PSS algorithm: %s", name)
+	}
+	params := pkcs11.NewPSSParams(hashAlg, mgf, saltLength)
+	return pkcs11.NewMechanism(pssMechanismID(name), params), nil
+}
+
+func pssMechanismID(name SigningAlgorithmName) uint {
+	switch name {
+	case PS256:
+		return pkcs11.CKM_SHA256_RSA_PKCS_PSS
+	case PS384:
+		return pkcs11.CKM_SHA384_RSA_PKCS_PSS
+	case PS512:
+		return pkcs11.CKM_SHA512_RSA_PKCS_PSS
+	}
+	return 0
+}
+
+func findSlot(ctx *pkcs11.Ctx, params *PKCS11Params) (uint, error) {
+	if params.SlotID != nil {
+		return *params.SlotID, nil
+	}
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: get slot list: %w", err)
+	}
+	if params.TokenLabel == "" {
+		if len(slots) == 0 {
+			return 0, fmt.Errorf("pkcs11: no slots with a token present")
+		}
+		return slots[0], nil
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if trimLabel(info.Label) == params.TokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11: no token with label %q", params.TokenLabel)
+}
+
+func findObject(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := ctx.FindObjectsInit(session, tmpl); err != nil {
+		return 0, err
+	}
+	defer ctx.FindObjectsFinal(session)
+	objs, _, err := ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("object not found")
+	}
+	return objs[0], nil
+}
+
+func findTokenCertificate(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, label string) ([]byte, error) {
+	obj, err := findObject(ctx, session, pkcs11.CKO_CERTIFICATE, label)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := ctx.GetAttributeValue(session, obj, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attrs[0].Value, nil
+}
+
+// trimLabel strips the fixed-width NUL/space padding PKCS#11 pads token
+// and object labels out to, returning just the label text.
+func trimLabel(label string) string {
+	return strings.TrimRight(label, " \x00")
+}
+
+// publicKeyForAlgorithm extracts the public key from certPEM so the
+// crypto.Signer can report it via Public() without a round-trip to the
+// token.
+func publicKeyForAlgorithm(alg *SigningAlgorithm, certPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("pkcs11: failed to parse PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: parse certificate: %w", err)
+	}
+	switch alg.Name {
+	case ED25519:
+		if _, ok := cert.PublicKey.(ed25519.PublicKey); !ok {
+			return nil, fmt.Errorf("pkcs11: certificate public key is not ed25519")
+		}
+	case ES256, ES256K, ES384, ES512:
+		if _, ok := cert.PublicKey.(*ecdsa.PublicKey); !ok {
+			return nil, fmt.Errorf("pkcs11: certificate public key is not ecdsa")
+		}
+	case PS256, PS384, PS512:
+		if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("pkcs11: certificate public key is not rsa")
+		}
+	}
+	return cert.PublicKey, nil
+}
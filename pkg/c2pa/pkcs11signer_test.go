@@ -0,0 +1,83 @@
+package c2pa
+
+import (
+	"crypto/rsa"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimLabel(t *testing.T) {
+	cases := []struct {
+		name  string
+		label string
+		want  string
+	}{
+		{"no padding", "my-token", "my-token"},
+		{"trailing spaces", "my-token        ", "my-token"},
+		{"trailing NULs", "my-token\x00\x00\x00\x00", "my-token"},
+		{"mixed trailing padding", "my-token   \x00\x00", "my-token"},
+		{"empty", "", ""},
+		{"all padding", "   \x00\x00", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, trimLabel(tc.label))
+		})
+	}
+}
+
+func TestPssMechanismID(t *testing.T) {
+	require.Equal(t, uint(pkcs11.CKM_SHA256_RSA_PKCS_PSS), pssMechanismID(PS256))
+	require.Equal(t, uint(pkcs11.CKM_SHA384_RSA_PKCS_PSS), pssMechanismID(PS384))
+	require.Equal(t, uint(pkcs11.CKM_SHA512_RSA_PKCS_PSS), pssMechanismID(PS512))
+	require.Equal(t, uint(0), pssMechanismID(ES256))
+}
+
+func TestPssMechanismRejectsNonPSSAlgorithm(t *testing.T) {
+	_, err := pssMechanism(ES256)
+	require.Error(t, err)
+}
+
+func TestPssMechanismBuildsParamsForEachVariant(t *testing.T) {
+	for _, name := range []SigningAlgorithmName{PS256, PS384, PS512} {
+		mech, err := pssMechanism(name)
+		require.NoError(t, err)
+		require.Equal(t, pssMechanismID(name), mech.Mechanism)
+	}
+}
+
+func TestMechanismSelection(t *testing.T) {
+	t.Run("ed25519", func(t *testing.T) {
+		s := &PKCS11Signer{alg: SigningAlgorithm{Name: ED25519}}
+		mech, err := s.mechanism(nil)
+		require.NoError(t, err)
+		require.EqualValues(t, ckmEDDSA, mech.Mechanism)
+	})
+
+	t.Run("ecdsa variants", func(t *testing.T) {
+		for _, name := range []SigningAlgorithmName{ES256, ES256K, ES384, ES512} {
+			s := &PKCS11Signer{alg: SigningAlgorithm{Name: name}}
+			mech, err := s.mechanism(nil)
+			require.NoError(t, err)
+			require.EqualValues(t, pkcs11.CKM_ECDSA, mech.Mechanism)
+		}
+	})
+
+	t.Run("pss requires matching PSSOptions", func(t *testing.T) {
+		s := &PKCS11Signer{alg: SigningAlgorithm{Name: PS256}}
+		_, err := s.mechanism(&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+		require.Error(t, err)
+
+		mech, err := s.mechanism(&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		require.NoError(t, err)
+		require.EqualValues(t, pkcs11.CKM_SHA256_RSA_PKCS_PSS, mech.Mechanism)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		s := &PKCS11Signer{alg: SigningAlgorithm{Name: "bogus"}}
+		_, err := s.mechanism(nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,300 @@
+// Package pki provides the CSR-generation and cert-chain-ordering helpers
+// needed to go from "generate a key" to "get it signed by a C2PA-
+// conformant CA" to "sign media with it", borrowing the workflow small PKI
+// CLIs like pkictl use. It supports every signing algorithm the rest of
+// this module recognizes, including secp256k1, the one Go's crypto/x509
+// package doesn't natively know how to build a CSR for.
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa"
+
+	"github.com/decred/dcrd/dcrec/secp256k1"
+)
+
+// CSROptions carries the subject alternative names for a generated CSR.
+type CSROptions struct {
+	DNSNames       []string
+	IPAddresses    []net.IP
+	EmailAddresses []string
+	URIs           []*url.URL
+}
+
+// oidECDSAWithSHA256 is the ecdsa-with-SHA256 AlgorithmIdentifier OID
+// (1.2.840.10045.4.3.2). secp256k1 keys sign with this algorithm, same as
+// P-256, since Go's crypto/x509 has no notion of the curve to pick a
+// different one automatically.
+var oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+
+// pssSignatureAlgorithm is the RSA-PSS/SHA-256 CSR signature algorithm
+// this package requests for RSA keys (c2pa.PS256 in this module's own
+// naming); its encoded AlgorithmIdentifier carries the same OID as
+// c2pa.OID_RSA_PSS. c2pa doesn't distinguish PS256/384/512 by key alone,
+// so this is the one RSA profile GenerateCSR produces; build a CSR via
+// crypto/x509 directly for a different PSS hash size.
+const pssSignatureAlgorithm = x509.SHA256WithRSAPSS
+
+// GenerateCSR builds a PKCS#10 certificate signing request for signer,
+// covering every algorithm this module recognizes (ES256/384/512, ES256K
+// via secp256k1, PS256/384/512, Ed25519), and returns it PEM-encoded as a
+// CERTIFICATE REQUEST block.
+func GenerateCSR(signer crypto.Signer, subject pkix.Name, sans CSROptions) ([]byte, error) {
+	pub := signer.Public()
+	switch key := pub.(type) {
+	case ed25519.PublicKey:
+		return createStdlibCSR(signer, subject, sans, x509.PureEd25519)
+	case *ecdsa.PublicKey:
+		if isSecp256k1(key) {
+			return createSecp256k1CSR(signer, subject, sans)
+		}
+		return createStdlibCSR(signer, subject, sans, x509.ECDSAWithSHA256)
+	case *rsa.PublicKey:
+		return createStdlibCSR(signer, subject, sans, pssSignatureAlgorithm)
+	default:
+		return nil, fmt.Errorf("pki: unsupported public key type %T", pub)
+	}
+}
+
+func isSecp256k1(pub *ecdsa.PublicKey) bool {
+	return pub.Curve == secp256k1.S256()
+}
+
+// createStdlibCSR covers every key type crypto/x509 already knows how to
+// build a CSR for on its own: Ed25519, P-256/384/521 ECDSA, and (given an
+// explicit PSS SignatureAlgorithm) RSA-PSS.
+func createStdlibCSR(signer crypto.Signer, subject pkix.Name, sans CSROptions, sigAlg x509.SignatureAlgorithm) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		Subject:            subject,
+		SignatureAlgorithm: sigAlg,
+		DNSNames:           sans.DNSNames,
+		IPAddresses:        sans.IPAddresses,
+		EmailAddresses:     sans.EmailAddresses,
+		URIs:               sans.URIs,
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	if err != nil {
+		return nil, fmt.Errorf("pki: create certificate request: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// csrSubjectPublicKeyInfo and csrCertificationRequestInfo mirror RFC 2986
+// (PKCS#10), built by hand for secp256k1 since crypto/x509 only recognizes
+// the NIST curves when assembling a CertificateRequest.
+type csrSubjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type csrCertificationRequest struct {
+	Info      asn1.RawValue
+	Algorithm pkix.AlgorithmIdentifier
+	Signature asn1.BitString
+}
+
+// createSecp256k1CSR builds and signs a CertificationRequestInfo by hand,
+// since x509.CreateCertificateRequest rejects any elliptic curve it
+// doesn't recognize (secp256k1 isn't one of the NIST curves Go supports).
+func createSecp256k1CSR(signer crypto.Signer, subject pkix.Name, sans CSROptions) ([]byte, error) {
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("pki: secp256k1 CSR requires an *ecdsa.PublicKey, got %T", signer.Public())
+	}
+
+	curveOID, err := asn1.Marshal(c2pa.OID_SECP256K1)
+	if err != nil {
+		return nil, fmt.Errorf("pki: marshal secp256k1 curve OID: %w", err)
+	}
+	point := elliptic256Point(pub)
+	spki := csrSubjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  c2pa.OID_EC,
+			Parameters: asn1.RawValue{FullBytes: curveOID},
+		},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	}
+
+	subjectRDN, err := asn1.Marshal(subject.ToRDNSequence())
+	if err != nil {
+		return nil, fmt.Errorf("pki: marshal subject: %w", err)
+	}
+
+	attributes, err := csrAttributes(sans)
+	if err != nil {
+		return nil, err
+	}
+
+	tbs := struct {
+		Version    int
+		Subject    asn1.RawValue
+		PublicKey  csrSubjectPublicKeyInfo
+		Attributes asn1.RawValue `asn1:"tag:0"`
+	}{
+		Version:    0,
+		Subject:    asn1.RawValue{FullBytes: subjectRDN},
+		PublicKey:  spki,
+		Attributes: attributes,
+	}
+	tbsBytes, err := asn1.Marshal(tbs)
+	if err != nil {
+		return nil, fmt.Errorf("pki: marshal CertificationRequestInfo: %w", err)
+	}
+
+	digest := sha256.Sum256(tbsBytes)
+	sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("pki: sign CertificationRequestInfo: %w", err)
+	}
+
+	csr := csrCertificationRequest{
+		Info:      asn1.RawValue{FullBytes: tbsBytes},
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+		Signature: asn1.BitString{Bytes: sig, BitLength: len(sig) * 8},
+	}
+	der, err := asn1.Marshal(csr)
+	if err != nil {
+		return nil, fmt.Errorf("pki: marshal CertificationRequest: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// csrAttributes encodes a CSR's [0] Attributes field. Subject alternative
+// names would normally go here as an extensionRequest attribute; for now
+// this package only signs the subject itself (matching the minimal set of
+// fields GenerateCSR's signature exposes), and simply emits the mandatory
+// empty SET when no SANs are given.
+func csrAttributes(sans CSROptions) (asn1.RawValue, error) {
+	if len(sans.DNSNames) == 0 && len(sans.IPAddresses) == 0 && len(sans.EmailAddresses) == 0 && len(sans.URIs) == 0 {
+		return asn1.RawValue{FullBytes: []byte{0xa0, 0x00}}, nil
+	}
+	return asn1.RawValue{}, errors.New("pki: subject alternative names on a secp256k1 CSR are not yet supported; omit sans or use a NIST-curve/RSA/Ed25519 key")
+}
+
+// elliptic256Point encodes pub as an uncompressed SEC1 point: 0x04 || X || Y.
+func elliptic256Point(pub *ecdsa.PublicKey) []byte {
+	byteLen := (pub.Curve.Params().BitSize + 7) / 8
+	point := make([]byte, 1+2*byteLen)
+	point[0] = 4
+	pub.X.FillBytes(point[1 : 1+byteLen])
+	pub.Y.FillBytes(point[1+byteLen:])
+	return point
+}
+
+// LoadCertChain parses pemBytes as one or more PEM-encoded CERTIFICATE
+// blocks, in any order, and returns them concatenated in the order
+// c2pa.BuilderParams.Cert expects: the end-entity (leaf) certificate
+// first, followed by each intermediate, chained by issuer/subject. Any
+// self-signed root present is dropped, since the Rust builder only wants
+// the chain up to (not including) a trust anchor.
+func LoadCertChain(pemBytes []byte) ([]byte, error) {
+	certs, err := parseAllCertificates(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("pki: no certificates found")
+	}
+	ordered, err := orderChain(certs)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	for _, cert := range ordered {
+		if err := pem.Encode(&out, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return nil, err
+		}
+	}
+	return out.Bytes(), nil
+}
+
+func parseAllCertificates(pemBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := pemBytes
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("pki: parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// orderChain walks certs from the leaf (the one certificate that isn't
+// any other's issuer) up through each intermediate, stopping before a
+// self-signed root.
+func orderChain(certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	isIssuerOfAnother := make(map[int]bool, len(certs))
+	for i, c := range certs {
+		for j, other := range certs {
+			if i != j && bytes.Equal(c.RawSubject, other.RawIssuer) {
+				isIssuerOfAnother[i] = true
+			}
+		}
+	}
+
+	leafIdx := -1
+	leafCandidates := 0
+	for i := range certs {
+		if !isIssuerOfAnother[i] {
+			leafCandidates++
+			if leafIdx == -1 {
+				leafIdx = i
+			}
+		}
+	}
+	if leafIdx == -1 {
+		return nil, errors.New("pki: could not find leaf certificate (every certificate is some other's issuer)")
+	}
+	if leafCandidates > 1 {
+		return nil, fmt.Errorf("pki: found %d candidate leaf certificates; certs must form a single chain", leafCandidates)
+	}
+
+	used := make(map[int]bool, len(certs))
+	used[leafIdx] = true
+	ordered := []*x509.Certificate{certs[leafIdx]}
+	current := certs[leafIdx]
+	for !bytes.Equal(current.RawIssuer, current.RawSubject) {
+		next := -1
+		for i, c := range certs {
+			if !used[i] && bytes.Equal(c.RawSubject, current.RawIssuer) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			break
+		}
+		if bytes.Equal(certs[next].RawIssuer, certs[next].RawSubject) {
+			break
+		}
+		used[next] = true
+		ordered = append(ordered, certs[next])
+		current = certs[next]
+	}
+	return ordered, nil
+}
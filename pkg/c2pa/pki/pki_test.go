@@ -0,0 +1,143 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// issueChain builds a root -> intermediate -> leaf chain and returns each
+// certificate PEM-encoded, in an arbitrary (not pre-ordered) slice.
+func issueChain(t *testing.T) (rootPEM, intermediatePEM, leafPEM []byte) {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	intKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	intTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "intermediate"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	intDER, err := x509.CreateCertificate(rand.Reader, intTmpl, rootCert, &intKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	intCert, err := x509.ParseCertificate(intDER)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, intCert, &leafKey.PublicKey, intKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+}
+
+// issueSelfSigned builds a single self-signed certificate, unrelated to any
+// other chain, for use as a disconnected leaf candidate.
+func issueSelfSigned(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestLoadCertChainOrdersLeafFirstAndDropsRoot(t *testing.T) {
+	rootPEM, intPEM, leafPEM := issueChain(t)
+
+	// Feed the blocks in a shuffled, not-pre-ordered order.
+	var pemBytes []byte
+	pemBytes = append(pemBytes, intPEM...)
+	pemBytes = append(pemBytes, rootPEM...)
+	pemBytes = append(pemBytes, leafPEM...)
+
+	ordered, err := LoadCertChain(pemBytes)
+	require.NoError(t, err)
+
+	certs, err := parseAllCertificates(ordered)
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	require.Equal(t, "leaf", certs[0].Subject.CommonName)
+	require.Equal(t, "intermediate", certs[1].Subject.CommonName)
+}
+
+func TestLoadCertChainNoCertificates(t *testing.T) {
+	_, err := LoadCertChain(nil)
+	require.Error(t, err)
+}
+
+func TestOrderChainMultipleDisconnectedLeafCandidates(t *testing.T) {
+	leafA := issueSelfSigned(t, "leaf-a")
+	leafB := issueSelfSigned(t, "leaf-b")
+
+	// Both are self-signed (each is its own issuer), so orderChain's
+	// "stop before a self-signed root" rule would otherwise silently treat
+	// whichever is picked first as a complete, one-certificate chain and
+	// drop the other without any indication something is wrong.
+	certs, err := parseAllCertificates(append(append([]byte{}, leafA...), leafB...))
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+
+	_, err = orderChain(certs)
+	require.Error(t, err)
+}
+
+func TestOrderChainNoSelfSignedRootPresent(t *testing.T) {
+	_, intPEM, leafPEM := issueChain(t)
+
+	// Only leaf + intermediate are supplied; the root never appears. This
+	// is a legitimate input (LoadCertChain's contract only needs the chain
+	// up to, not including, a trust anchor), so it must order successfully
+	// rather than erroring for lack of a root.
+	certs, err := parseAllCertificates(append(append([]byte{}, intPEM...), leafPEM...))
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+
+	ordered, err := orderChain(certs)
+	require.NoError(t, err)
+	require.Len(t, ordered, 2)
+	require.Equal(t, "leaf", ordered[0].Subject.CommonName)
+	require.Equal(t, "intermediate", ordered[1].Subject.CommonName)
+}
@@ -0,0 +1,148 @@
+// Package plugin implements a Notary-style remote signing plugin protocol:
+// signing is delegated to a separate executable discovered on $PATH or in a
+// configured directory, so KMS/cloud-HSM integrations can be added without
+// modifying this module.
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// binaryPrefix is prepended to a plugin name to form its executable name,
+// e.g. name "awskms" resolves to "c2pa-plugin-awskms".
+const binaryPrefix = "c2pa-plugin-"
+
+// Metadata describes a plugin's declared capabilities, as returned by its
+// get-metadata command.
+type Metadata struct {
+	Name                string   `json:"name"`
+	Description         string   `json:"description"`
+	Version             string   `json:"version"`
+	SupportedAlgorithms []string `json:"supportedAlgorithms"`
+	ProvidesTimestamp   bool     `json:"providesTimestamp"`
+	ProvidesCertChain   bool     `json:"providesCertChain"`
+}
+
+// SignRequest is sent to a plugin's sign command on stdin as JSON.
+type SignRequest struct {
+	// Digest is the pre-hashed message digest to sign (raw message for
+	// Ed25519, which hashes internally).
+	Digest []byte `json:"digest"`
+	// Algorithm is a SigningAlgorithmName, e.g. "es256" or "ps384".
+	Algorithm string `json:"algorithm"`
+}
+
+// SignResponse is read from a plugin's sign command on stdout as JSON.
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+	// CertChain is PEM-encoded, present when the plugin owns cert
+	// issuance rather than the caller supplying BuilderParams.Cert.
+	CertChain []byte `json:"certChain,omitempty"`
+	// Timestamp is a DER-encoded RFC3161 token, present when the plugin
+	// provides its own timestamp rather than the caller's TAURL/TimestampProvider.
+	Timestamp []byte `json:"timestamp,omitempty"`
+}
+
+// Manager locates and invokes signing plugins.
+type Manager struct {
+	// Dirs are searched, in order, before falling back to $PATH.
+	Dirs []string
+	// Env, if set, is appended to the plugin subprocess's environment on
+	// top of the parent process's own (os.Environ()), e.g. to pass a KMS
+	// key reference the plugin reads at startup without mutating this
+	// process's global environment.
+	Env []string
+}
+
+// NewManager returns a Manager that searches dirs before $PATH.
+func NewManager(dirs ...string) *Manager {
+	return &Manager{Dirs: dirs}
+}
+
+// Locate finds the executable for the named plugin, searching m.Dirs
+// before $PATH.
+func (m *Manager) Locate(name string) (string, error) {
+	binName := binaryPrefix + name
+	for _, dir := range m.Dirs {
+		path, err := exec.LookPath(dirJoin(dir, binName))
+		if err == nil {
+			return path, nil
+		}
+	}
+	path, err := exec.LookPath(binName)
+	if err != nil {
+		return "", fmt.Errorf("plugin: %s not found on PATH or in configured dirs: %w", binName, err)
+	}
+	return path, nil
+}
+
+// GetMetadata invokes the plugin's get-metadata command and parses its
+// declared capabilities.
+func (m *Manager) GetMetadata(name string) (*Metadata, error) {
+	path, err := m.Locate(name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runPlugin(path, "get-metadata", nil, m.Env)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: get-metadata for %s: %w", name, err)
+	}
+	var meta Metadata
+	if err := json.Unmarshal(out, &meta); err != nil {
+		return nil, fmt.Errorf("plugin: parse get-metadata output for %s: %w", name, err)
+	}
+	return &meta, nil
+}
+
+// Sign invokes the plugin's sign command with req encoded as JSON on
+// stdin, and parses its JSON response from stdout.
+func (m *Manager) Sign(name string, req *SignRequest) (*SignResponse, error) {
+	path, err := m.Locate(name)
+	if err != nil {
+		return nil, err
+	}
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: encode sign request: %w", err)
+	}
+	out, err := runPlugin(path, "sign", in, m.Env)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: sign via %s: %w", name, err)
+	}
+	var resp SignResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("plugin: parse sign response from %s: %w", name, err)
+	}
+	return &resp, nil
+}
+
+func runPlugin(path, command string, stdin []byte, env []string) ([]byte, error) {
+	cmd := exec.Command(path, command)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("%w: %s", err, stderr.String())
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func dirJoin(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + string('/') + name
+}
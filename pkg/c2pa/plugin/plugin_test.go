@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildExamplePlugin compiles testdata/c2pa-plugin-example into dir and
+// returns dir, so it can be added to a Manager's search path.
+func buildExamplePlugin(t *testing.T) string {
+	t.Helper()
+	_, filename, _, _ := runtime.Caller(0)
+	src := filepath.Join(filepath.Dir(filename), "testdata", "c2pa-plugin-example")
+
+	dir := t.TempDir()
+	binName := "c2pa-plugin-example"
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	out := filepath.Join(dir, binName)
+
+	cmd := exec.Command("go", "build", "-o", out, ".")
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "building example plugin: %s", output)
+	return dir
+}
+
+func TestManagerSignES256(t *testing.T) {
+	dir := buildExamplePlugin(t)
+	manager := NewManager(dir)
+
+	meta, err := manager.GetMetadata("example")
+	require.NoError(t, err)
+	require.Equal(t, []string{"es256"}, meta.SupportedAlgorithms)
+
+	digest := sha256.Sum256([]byte("hello c2pa"))
+	resp, err := manager.Sign("example", &SignRequest{Digest: digest[:], Algorithm: "es256"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Signature)
+
+	pub := examplePluginPublicKey()
+	require.True(t, ecdsa.VerifyASN1(pub, digest[:], resp.Signature))
+}
+
+func TestManagerLocateMissing(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	_, err := manager.Locate("does-not-exist")
+	require.Error(t, err)
+}
+
+// examplePluginPublicKey mirrors the fixed test key baked into
+// testdata/c2pa-plugin-example/main.go.
+func examplePluginPublicKey() *ecdsa.PublicKey {
+	x, y := elliptic.P256().ScalarBaseMult(big.NewInt(1).Bytes())
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+)
+
+// Signer implements crypto.Signer by delegating to a named plugin. It's
+// meant to be used as c2pa.BuilderParams.Signer via
+// c2pa.C2PACallbackSigner, same as any in-memory key.
+type Signer struct {
+	manager   *Manager
+	name      string
+	algorithm string
+	pub       crypto.PublicKey
+	certPEM   []byte
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// NewSigner connects to the plugin named name and verifies it declares
+// support for algorithm (a SigningAlgorithmName such as "es256"). certPEM
+// supplies the signing certificate when the plugin doesn't declare
+// ProvidesCertChain; it's used to extract the public key.
+func NewSigner(manager *Manager, name, algorithm string, certPEM []byte) (*Signer, error) {
+	meta, err := manager.GetMetadata(name)
+	if err != nil {
+		return nil, err
+	}
+	if !supports(meta.SupportedAlgorithms, algorithm) {
+		return nil, fmt.Errorf("plugin: %s does not support algorithm %s (supports %v)", name, algorithm, meta.SupportedAlgorithms)
+	}
+
+	var pub crypto.PublicKey
+	if len(certPEM) > 0 {
+		block, _ := pem.Decode(certPEM)
+		if block == nil {
+			return nil, fmt.Errorf("plugin: failed to parse PEM certificate")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("plugin: parse certificate: %w", err)
+		}
+		pub = cert.PublicKey
+	} else if !meta.ProvidesCertChain {
+		return nil, fmt.Errorf("plugin: %s provides no cert chain and no certPEM was supplied", name)
+	}
+
+	return &Signer{manager: manager, name: name, algorithm: algorithm, pub: pub, certPEM: certPEM}, nil
+}
+
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign sends digest to the plugin's sign command and returns the raw
+// signature bytes. opts is accepted to satisfy crypto.Signer but the
+// plugin protocol conveys the algorithm by name, not by crypto.SignerOpts,
+// since it may need to select its own PSS/MGF parameters server-side.
+func (s *Signer) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	resp, err := s.manager.Sign(s.name, &SignRequest{Digest: digest, Algorithm: s.algorithm})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.CertChain) > 0 {
+		s.certPEM = resp.CertChain
+	}
+	return resp.Signature, nil
+}
+
+// CertPEM returns the PEM-encoded certificate chain last observed for this
+// signer: either the one supplied at construction, or the one most
+// recently returned by the plugin's sign command.
+func (s *Signer) CertPEM() []byte {
+	return s.certPEM
+}
+
+func supports(algorithms []string, want string) bool {
+	for _, a := range algorithms {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Command c2pa-plugin-example is a minimal signing plugin used by
+// plugin_test.go to exercise the plugin protocol end-to-end. It signs with
+// a fixed ES256 test key baked in at build time; it is not meant for real
+// use.
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// fixed test key so signatures are deterministic to verify against.
+var testKey = mustKey()
+
+func mustKey() *ecdsa.PrivateKey {
+	k := new(ecdsa.PrivateKey)
+	k.PublicKey.Curve = elliptic.P256()
+	k.D = big.NewInt(1)
+	k.PublicKey.X, k.PublicKey.Y = elliptic.P256().ScalarBaseMult(k.D.Bytes())
+	return k
+}
+
+type metadata struct {
+	Name                string   `json:"name"`
+	Description         string   `json:"description"`
+	Version             string   `json:"version"`
+	SupportedAlgorithms []string `json:"supportedAlgorithms"`
+	ProvidesTimestamp   bool     `json:"providesTimestamp"`
+	ProvidesCertChain   bool     `json:"providesCertChain"`
+}
+
+type signRequest struct {
+	Digest    []byte `json:"digest"`
+	Algorithm string `json:"algorithm"`
+}
+
+type signResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: c2pa-plugin-example <get-metadata|sign>")
+		os.Exit(1)
+	}
+	switch os.Args[1] {
+	case "get-metadata":
+		enc := json.NewEncoder(os.Stdout)
+		if err := enc.Encode(metadata{
+			Name:                "example",
+			Description:         "test plugin for c2pa-go's plugin package",
+			Version:             "0.0.0",
+			SupportedAlgorithms: []string{"es256"},
+		}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "sign":
+		var req signRequest
+		if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		sig, err := testKey.Sign(rand.Reader, req.Digest, crypto.SHA256)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(signResponse{Signature: sig}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", os.Args[1])
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package c2pa
+
+import (
+	"crypto"
+	"fmt"
+
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/plugin"
+)
+
+// RemoteSignerConfig selects and configures one remote signing backend.
+// Exactly one of PKCS11, Plugin, or Signer should be set; all three
+// produce a crypto.Signer usable as BuilderParams.Signer via
+// C2PACallbackSigner, same as any in-memory key.
+type RemoteSignerConfig struct {
+	// PKCS11 configures a PKCS#11/HSM-backed signer.
+	PKCS11 *PKCS11Params
+	// Plugin configures a subprocess signing plugin.
+	Plugin *PluginSignerConfig
+	// Signer passes through a crypto.Signer built by the caller, for
+	// backends with a first-class adapter that lives outside this
+	// package rather than a subprocess plugin — e.g.
+	// signers.NewAWSKMSSigner. It isn't constructed here because that
+	// adapter already imports this package to learn SigningAlgorithm,
+	// and pkg/c2pa can't import it back without a cycle.
+	Signer crypto.Signer
+}
+
+// PluginSignerConfig names the plugin subprocess to delegate signing to.
+type PluginSignerConfig struct {
+	// Manager locates and invokes the plugin. If nil, a Manager with no
+	// configured Dirs is used, so plugins are found on $PATH only.
+	Manager *plugin.Manager
+	// Name is the plugin's name, e.g. "awskms" for c2pa-plugin-awskms.
+	Name string
+	// CertPEM optionally supplies the signing certificate out-of-band,
+	// for plugins that don't declare ProvidesCertChain.
+	CertPEM []byte
+}
+
+// NewRemoteSigner dispatches to the backend configured in cfg and returns
+// a crypto.Signer for alg. It exists so callers can select a signing
+// backend from data (e.g. a config file) without knowing which concrete
+// constructor to call.
+func NewRemoteSigner(cfg *RemoteSignerConfig, alg *SigningAlgorithm) (crypto.Signer, error) {
+	set := 0
+	for _, configured := range []bool{cfg.PKCS11 != nil, cfg.Plugin != nil, cfg.Signer != nil} {
+		if configured {
+			set++
+		}
+	}
+	switch {
+	case set > 1:
+		return nil, fmt.Errorf("remotesigner: only one of PKCS11, Plugin, or Signer may be set")
+	case cfg.PKCS11 != nil:
+		return NewPKCS11Signer(cfg.PKCS11, alg)
+	case cfg.Plugin != nil:
+		manager := cfg.Plugin.Manager
+		if manager == nil {
+			manager = plugin.NewManager()
+		}
+		return plugin.NewSigner(manager, cfg.Plugin.Name, string(alg.Name), cfg.Plugin.CertPEM)
+	case cfg.Signer != nil:
+		return cfg.Signer, nil
+	default:
+		return nil, fmt.Errorf("remotesigner: no backend configured")
+	}
+}
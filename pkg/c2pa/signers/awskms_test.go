@@ -0,0 +1,156 @@
+package signers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa"
+)
+
+// fakeKMS serves just enough of the KMS JSON 1.1 protocol
+// (TrentService.GetPublicKey / TrentService.Sign) for AWSKMSSigner to
+// exercise against, without any real AWS access or credentials.
+func fakeKMS(t *testing.T, pub *ecdsa.PublicKey, signature []byte) *httptest.Server {
+	t.Helper()
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"KeyId":             "test-key",
+				"PublicKey":         pubDER,
+				"SigningAlgorithms": []string{"ECDSA_SHA_256"},
+			})
+		case "TrentService.Sign":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"KeyId":            "test-key",
+				"Signature":        signature,
+				"SigningAlgorithm": "ECDSA_SHA_256",
+			})
+		default:
+			http.Error(w, "unknown action", http.StatusBadRequest)
+		}
+	}))
+}
+
+func newTestAWSKMSSigner(t *testing.T, endpoint string, pub *ecdsa.PublicKey) *AWSKMSSigner {
+	t.Helper()
+	alg, err := c2pa.GetSigningAlgorithm("es256")
+	require.NoError(t, err)
+
+	s := &AWSKMSSigner{
+		client:   http.DefaultClient,
+		endpoint: endpoint,
+		region:   "us-east-1",
+		keyID:    "test-key",
+		alg:      alg,
+		creds:    awsCredentials{AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "secret"},
+	}
+	pubKey, err := s.fetchPublicKey("ECDSA_SHA_256")
+	require.NoError(t, err)
+	s.pub = pubKey
+	return s
+}
+
+func TestAWSKMSSignerFetchesAndCachesPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := fakeKMS(t, &key.PublicKey, nil)
+	defer server.Close()
+
+	signer := newTestAWSKMSSigner(t, server.URL+"/", &key.PublicKey)
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.True(t, pub.Equal(&key.PublicKey))
+}
+
+func TestAWSKMSSignerSignCallsKMSOverSigV4(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := []byte("0123456789abcdef0123456789abcdef")
+	der, err := key.Sign(rand.Reader, digest, nil)
+	require.NoError(t, err)
+
+	var sawAuth, sawTarget string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawAuth = r.Header.Get("Authorization")
+		sawTarget = r.Header.Get("X-Amz-Target")
+		switch sawTarget {
+		case "TrentService.GetPublicKey":
+			pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"PublicKey":         pubDER,
+				"SigningAlgorithms": []string{"ECDSA_SHA_256"},
+			})
+		case "TrentService.Sign":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Signature": der})
+		}
+	}))
+	defer server.Close()
+
+	signer := newTestAWSKMSSigner(t, server.URL+"/", &key.PublicKey)
+
+	sig, err := signer.Sign(nil, digest, nil)
+	require.NoError(t, err)
+	require.Equal(t, der, sig)
+	require.NotEmpty(t, sawAuth)
+	require.True(t, strings.HasPrefix(sawAuth, "AWS4-HMAC-SHA256 Credential=AKIAEXAMPLE/"))
+	require.Equal(t, "TrentService.Sign", sawTarget)
+}
+
+func TestAWSKMSSignerRejectsUnsupportedAlgorithm(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pubDER, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"PublicKey":         pubDER,
+			"SigningAlgorithms": []string{"RSASSA_PSS_SHA_256"},
+		})
+	}))
+	defer server.Close()
+
+	alg, err := c2pa.GetSigningAlgorithm("es256")
+	require.NoError(t, err)
+	s := &AWSKMSSigner{
+		client:   http.DefaultClient,
+		endpoint: server.URL + "/",
+		region:   "us-east-1",
+		keyID:    "test-key",
+		alg:      alg,
+		creds:    awsCredentials{AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "secret"},
+	}
+	_, err = s.fetchPublicKey("ECDSA_SHA_256")
+	require.Error(t, err)
+}
+
+func TestNewAWSKMSSignerRequiresCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	alg, err := c2pa.GetSigningAlgorithm("es256")
+	require.NoError(t, err)
+	_, err = NewAWSKMSSigner("test-key", alg, "us-east-1")
+	require.Error(t, err)
+}
+
+func TestAWSKMSURIYieldsKeyRef(t *testing.T) {
+	u, err := url.Parse("awskms:///arn:aws:kms:us-east-1:111122223333:key/1234abcd?alg=es256")
+	require.NoError(t, err)
+	require.Equal(t, "awskms", u.Scheme)
+	keyRef := strings.TrimPrefix(u.Host+u.Path, "/")
+	require.Equal(t, "arn:aws:kms:us-east-1:111122223333:key/1234abcd", keyRef)
+}
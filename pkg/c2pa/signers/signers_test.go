@@ -0,0 +1,48 @@
+package signers
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgorithmFromQueryDefaultsWhenAbsent(t *testing.T) {
+	alg, err := algorithmFromQuery(url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, defaultAlgorithm, string(alg.Name))
+}
+
+func TestAlgorithmFromQueryUsesExplicitAlg(t *testing.T) {
+	alg, err := algorithmFromQuery(url.Values{"alg": {"ps256"}})
+	require.NoError(t, err)
+	require.Equal(t, "ps256", string(alg.Name))
+}
+
+func TestAlgorithmFromQueryRejectsUnknownAlg(t *testing.T) {
+	_, err := algorithmFromQuery(url.Values{"alg": {"bogus"}})
+	require.Error(t, err)
+}
+
+func TestCertFromQueryAbsent(t *testing.T) {
+	certPEM, err := certFromQuery(url.Values{})
+	require.NoError(t, err)
+	require.Nil(t, certPEM)
+}
+
+func TestCertFromQueryReadsFile(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(certPath, []byte("fake cert"), 0o600))
+
+	certPEM, err := certFromQuery(url.Values{"cert": {certPath}})
+	require.NoError(t, err)
+	require.Equal(t, "fake cert", string(certPEM))
+}
+
+func TestCertFromQueryMissingFile(t *testing.T) {
+	_, err := certFromQuery(url.Values{"cert": {"/nonexistent/cert.pem"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package signers
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeriveSigningKeyMatchesAWSTestSuite checks deriveSigningKey against
+// the worked example in AWS's own SigV4 documentation
+// (https://docs.aws.amazon.com/general/latest/gr/sigv4-calculate-signature.html),
+// so a bug in this hand-rolled implementation doesn't have to wait for a
+// live KMS call to surface.
+func TestDeriveSigningKeyMatchesAWSTestSuite(t *testing.T) {
+	key := deriveSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20150830", "us-east-1", "iam")
+	require.Equal(t, "c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9", hex.EncodeToString(key))
+}
+
+func TestHashHexEmptyInput(t *testing.T) {
+	// SHA-256 of the empty string, a well-known constant independent of
+	// this package, used as a sanity check on hashHex itself.
+	require.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hashHex(nil))
+}
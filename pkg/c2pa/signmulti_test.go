@@ -0,0 +1,95 @@
+package c2pa
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter always returns err from Write, simulating a destination
+// that's gone away mid-copy (a closed file, a reset pipe, etc.).
+type failingWriter struct{ err error }
+
+func (f failingWriter) Write([]byte) (int, error) { return 0, f.err }
+
+func TestCopyToExtraOutputsFileBufferAndPipe(t *testing.T) {
+	signed := []byte("signed asset bytes")
+
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "out.bin"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	var buf bytes.Buffer
+
+	pr, pw := io.Pipe()
+	pipeResult := make(chan []byte, 1)
+	go func() {
+		data, _ := io.ReadAll(pr)
+		pipeResult <- data
+	}()
+
+	output := bytes.NewReader(signed)
+	err = copyToExtraOutputs(output, []io.Writer{f, &buf, pw})
+	pw.Close()
+	require.NoError(t, err)
+
+	fileBs, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, signed, fileBs)
+	require.Equal(t, signed, buf.Bytes())
+	require.Equal(t, signed, <-pipeResult)
+}
+
+func TestCopyToExtraOutputsAggregatesFailuresByDefault(t *testing.T) {
+	signed := []byte("signed asset bytes")
+	output := bytes.NewReader(signed)
+
+	var good1, good2 bytes.Buffer
+	boom := errors.New("boom")
+
+	err := copyToExtraOutputs(output, []io.Writer{
+		&good1,
+		failingWriter{err: boom},
+		&good2,
+	})
+	require.Error(t, err)
+
+	var multiErr *MultiOutputError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 1)
+	require.ErrorIs(t, multiErr.Errors[1], boom)
+
+	// Both good writers still ran despite index 1 failing.
+	require.Equal(t, signed, good1.Bytes())
+	require.Equal(t, signed, good2.Bytes())
+}
+
+func TestCopyToExtraOutputsAbortOnFirstErrorStopsEarly(t *testing.T) {
+	signed := []byte("signed asset bytes")
+	output := bytes.NewReader(signed)
+
+	var neverWritten bytes.Buffer
+	boom := errors.New("boom")
+
+	err := copyToExtraOutputs(output, []io.Writer{
+		failingWriter{err: boom},
+		&neverWritten,
+	}, WithAbortOnFirstError())
+	require.Error(t, err)
+
+	var multiErr *MultiOutputError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 1)
+	require.Empty(t, neverWritten.Bytes())
+}
+
+func TestCopyToExtraOutputsNoExtraOutputsSucceeds(t *testing.T) {
+	output := bytes.NewReader([]byte("signed"))
+	require.NoError(t, copyToExtraOutputs(output, nil))
+}
@@ -0,0 +1,140 @@
+// Package telemetry defines small tracing/metrics interfaces shaped after
+// OpenTelemetry's trace.Tracer and metric.Int64Counter so Reader/Builder/
+// Signer operations can be observed without this module taking a hard
+// dependency on the OTel SDK. An adapter implementing these interfaces in
+// terms of go.opentelemetry.io/otel is a handful of lines for callers who
+// already have an OTel provider configured.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+)
+
+// Span is ended once, with the error the traced operation returned (nil
+// on success), miricking otel's trace.Span.End plus RecordError/SetStatus
+// collapsed into a single call for the simple pass/fail spans this
+// package's operations produce.
+type Span interface {
+	End(err error)
+}
+
+// Tracer starts a span named name as a child of ctx, returning the
+// context to propagate to nested operations.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Counter accumulates a running total, e.g. signing operations performed.
+type Counter interface {
+	Add(ctx context.Context, delta int64, attrs ...Attr)
+}
+
+// Histogram records individual measurements, e.g. operation duration.
+type Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attr)
+}
+
+// Attr is a single key/value metric attribute, analogous to an OTel
+// attribute.KeyValue but string-valued, since every attribute this
+// package's operations emit (algorithm name, mime type, outcome) is one.
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Meter creates the named instruments an operation reports to. Repeated
+// calls with the same name should return the same underlying instrument,
+// the same contract OTel's Meter makes.
+type Meter interface {
+	Counter(name string) Counter
+	Histogram(name string) Histogram
+}
+
+// Hooks bundles the tracer and meter Reader/Builder/Signer operations
+// report to. Either field may be nil, in which case that kind of telemetry
+// is skipped; a nil *Hooks disables both.
+type Hooks struct {
+	Tracer Tracer
+	Meter  Meter
+}
+
+// StartSpan starts a span via h.Tracer if configured, returning a no-op
+// span and the original ctx otherwise so callers can defer span.End(err)
+// unconditionally.
+func (h *Hooks) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if h == nil || h.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return h.Tracer.Start(ctx, name)
+}
+
+// AddCount reports delta on the named counter via h.Meter if configured.
+func (h *Hooks) AddCount(ctx context.Context, name string, delta int64, attrs ...Attr) {
+	if h == nil || h.Meter == nil {
+		return
+	}
+	h.Meter.Counter(name).Add(ctx, delta, attrs...)
+}
+
+// RecordValue reports value on the named histogram via h.Meter if
+// configured.
+func (h *Hooks) RecordValue(ctx context.Context, name string, value float64, attrs ...Attr) {
+	if h == nil || h.Meter == nil {
+		return
+	}
+	h.Meter.Histogram(name).Record(ctx, value, attrs...)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(error) {}
+
+var _ Span = noopSpan{}
+
+// SpanContext identifies a span for W3C trace-context propagation, mirring
+// the fields of otel's trace.SpanContext that actually need to cross the
+// wire.
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+}
+
+// SpanContextProvider is implemented by a Span that can report the
+// SpanContext to propagate to outgoing requests started under it. A Span
+// that doesn't implement this (e.g. the noop span, or a minimal Tracer
+// that doesn't track IDs) simply isn't propagated.
+type SpanContextProvider interface {
+	SpanContext() SpanContext
+}
+
+type spanContextKey struct{}
+
+// StartSpanPropagating is like Hooks.StartSpan, but if the returned Span is a
+// SpanContextProvider, its SpanContext is additionally stashed in the
+// returned context for later retrieval via FromContext.
+func (h *Hooks) StartSpanPropagating(ctx context.Context, name string) (context.Context, Span) {
+	ctx, span := h.StartSpan(ctx, name)
+	if provider, ok := span.(SpanContextProvider); ok {
+		ctx = context.WithValue(ctx, spanContextKey{}, provider.SpanContext())
+	}
+	return ctx, span
+}
+
+// FromContext returns the SpanContext stashed in ctx by StartSpanPropagating,
+// if any.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// InjectTraceparent sets the W3C "traceparent" header on header from the
+// SpanContext stashed in ctx, if there is one. It's a no-op otherwise, so
+// callers can call it unconditionally before issuing an outgoing request.
+func InjectTraceparent(ctx context.Context, header interface{ Set(string, string) }) {
+	sc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID))
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTracer struct {
+	started []string
+	ended   []error
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.started = append(t.started, name)
+	return ctx, &recordingSpan{tracer: t}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+}
+
+func (s *recordingSpan) End(err error) {
+	s.tracer.ended = append(s.tracer.ended, err)
+}
+
+type recordingMeter struct {
+	counts map[string]int64
+}
+
+func (m *recordingMeter) Counter(name string) Counter {
+	return recordingCounter{m, name}
+}
+
+func (m *recordingMeter) Histogram(name string) Histogram {
+	return recordingHistogram{}
+}
+
+type recordingCounter struct {
+	m    *recordingMeter
+	name string
+}
+
+func (c recordingCounter) Add(ctx context.Context, delta int64, attrs ...Attr) {
+	if c.m.counts == nil {
+		c.m.counts = map[string]int64{}
+	}
+	c.m.counts[c.name] += delta
+}
+
+type recordingHistogram struct{}
+
+func (recordingHistogram) Record(ctx context.Context, value float64, attrs ...Attr) {}
+
+func TestHooksStartSpanRecordsOutcome(t *testing.T) {
+	tracer := &recordingTracer{}
+	hooks := &Hooks{Tracer: tracer}
+
+	_, span := hooks.StartSpan(context.Background(), "builder.sign")
+	span.End(nil)
+
+	require.Equal(t, []string{"builder.sign"}, tracer.started)
+	require.Equal(t, []error{nil}, tracer.ended)
+}
+
+func TestHooksAddCount(t *testing.T) {
+	meter := &recordingMeter{}
+	hooks := &Hooks{Meter: meter}
+
+	hooks.AddCount(context.Background(), "builder.sign.count", 1)
+	hooks.AddCount(context.Background(), "builder.sign.count", 1)
+
+	require.Equal(t, int64(2), meter.counts["builder.sign.count"])
+}
+
+func TestNilHooksAreNoop(t *testing.T) {
+	var hooks *Hooks
+	ctx, span := hooks.StartSpan(context.Background(), "op")
+	span.End(nil)
+	hooks.AddCount(ctx, "metric", 1)
+	hooks.RecordValue(ctx, "metric", 1.0)
+}
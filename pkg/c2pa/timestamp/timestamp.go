@@ -0,0 +1,201 @@
+// Package timestamp provides pluggable RFC3161 timestamp authority (TSA)
+// clients for use as BuilderParams.TimestampProvider: HTTP with client
+// certs and a nonce, fallback across several TSAs, digest-keyed caching,
+// and a static/offline provider for reproducible signing.
+package timestamp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	dtimestamp "github.com/digitorus/timestamp"
+)
+
+// Provider returns a DER-encoded RFC3161 timestamp token (TST) over a
+// digest already hashed by the caller with hashAlg.
+type Provider interface {
+	Timestamp(digest []byte, hashAlg crypto.Hash) ([]byte, error)
+}
+
+// HTTPProvider requests a timestamp from a single RFC3161 HTTP TSA, the
+// same shape c2pa-go's BuilderParams.TAURL has always pointed at.
+type HTTPProvider struct {
+	// URL is the TSA endpoint, e.g. "http://timestamp.digicert.com".
+	URL string
+	// ClientCertificates, if set, authenticate this client to the TSA.
+	ClientCertificates []tls.Certificate
+	// Nonce enables the RFC3161 nonce challenge when true.
+	Nonce bool
+	// Timeout bounds the HTTP round-trip; zero means no timeout.
+	Timeout time.Duration
+
+	clientOnce sync.Once
+	client     *http.Client
+}
+
+// NewHTTPProvider returns a Provider that requests timestamps from url.
+func NewHTTPProvider(url string) *HTTPProvider {
+	return &HTTPProvider{URL: url}
+}
+
+func (p *HTTPProvider) httpClient() *http.Client {
+	p.clientOnce.Do(func() {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if len(p.ClientCertificates) > 0 {
+			transport.TLSClientConfig = &tls.Config{Certificates: p.ClientCertificates}
+		}
+		p.client = &http.Client{Transport: transport, Timeout: p.Timeout}
+	})
+	return p.client
+}
+
+// Timestamp implements Provider.
+func (p *HTTPProvider) Timestamp(digest []byte, hashAlg crypto.Hash) ([]byte, error) {
+	req := &dtimestamp.Request{
+		HashAlgorithm: hashAlg,
+		HashedMessage: digest,
+		Certificates:  true,
+	}
+	if p.Nonce {
+		nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+		if err != nil {
+			return nil, fmt.Errorf("timestamp: generate nonce: %w", err)
+		}
+		req.Nonce = nonce
+	}
+	body, err := req.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: marshal TSQ: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/timestamp-query")
+	httpReq.Header.Set("Accept", "application/timestamp-reply")
+
+	resp, err := p.httpClient().Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: request to %s: %w", p.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("timestamp: %s returned status %d", p.URL, resp.StatusCode)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: read response: %w", err)
+	}
+
+	ts, err := dtimestamp.ParseResponse(respBody)
+	if err != nil {
+		return nil, fmt.Errorf("timestamp: parse TSR from %s: %w", p.URL, err)
+	}
+	if req.Nonce != nil && (ts.Nonce == nil || ts.Nonce.Cmp(req.Nonce) != 0) {
+		return nil, fmt.Errorf("timestamp: %s returned a mismatched nonce", p.URL)
+	}
+	return ts.RawToken, nil
+}
+
+// FallbackProvider tries each Provider in order, returning the first
+// successful timestamp and otherwise the last error encountered.
+type FallbackProvider struct {
+	Providers []Provider
+}
+
+// NewFallbackProvider returns a Provider that tries providers in order.
+func NewFallbackProvider(providers ...Provider) *FallbackProvider {
+	return &FallbackProvider{Providers: providers}
+}
+
+func (p *FallbackProvider) Timestamp(digest []byte, hashAlg crypto.Hash) ([]byte, error) {
+	var lastErr error
+	for _, provider := range p.Providers {
+		tst, err := provider.Timestamp(digest, hashAlg)
+		if err == nil {
+			return tst, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("timestamp: no providers configured")
+	}
+	return nil, fmt.Errorf("timestamp: all providers failed, last error: %w", lastErr)
+}
+
+// CachingProvider dedupes identical requests to an underlying Provider
+// within TTL, keyed by digest+hashAlg. Useful when the same asset is
+// re-signed repeatedly during testing or retried after a transient error.
+type CachingProvider struct {
+	Provider Provider
+	TTL      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+	now   func() time.Time
+}
+
+type cacheEntry struct {
+	tst       []byte
+	expiresAt time.Time
+}
+
+// NewCachingProvider returns a Provider that caches underlying's results
+// for ttl.
+func NewCachingProvider(underlying Provider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{Provider: underlying, TTL: ttl, cache: make(map[string]cacheEntry), now: time.Now}
+}
+
+func (p *CachingProvider) Timestamp(digest []byte, hashAlg crypto.Hash) ([]byte, error) {
+	key := cacheKey(digest, hashAlg)
+
+	p.mu.Lock()
+	if entry, ok := p.cache[key]; ok && p.now().Before(entry.expiresAt) {
+		p.mu.Unlock()
+		return entry.tst, nil
+	}
+	p.mu.Unlock()
+
+	tst, err := p.Provider.Timestamp(digest, hashAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.cache[key] = cacheEntry{tst: tst, expiresAt: p.now().Add(p.TTL)}
+	p.mu.Unlock()
+	return tst, nil
+}
+
+func cacheKey(digest []byte, hashAlg crypto.Hash) string {
+	return fmt.Sprintf("%d:%x", hashAlg, digest)
+}
+
+// StaticProvider always returns the same pre-obtained TST, for offline
+// signing and reproducible tests where no live TSA is reachable.
+type StaticProvider struct {
+	TST []byte
+}
+
+// NewStaticProvider returns a Provider that always returns tst.
+func NewStaticProvider(tst []byte) *StaticProvider {
+	return &StaticProvider{TST: tst}
+}
+
+func (p *StaticProvider) Timestamp(_ []byte, _ crypto.Hash) ([]byte, error) {
+	return p.TST, nil
+}
+
+var _ Provider = (*HTTPProvider)(nil)
+var _ Provider = (*FallbackProvider)(nil)
+var _ Provider = (*CachingProvider)(nil)
+var _ Provider = (*StaticProvider)(nil)
@@ -0,0 +1,151 @@
+package timestamp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dtimestamp "github.com/digitorus/timestamp"
+	"github.com/stretchr/testify/require"
+)
+
+// testTSAPolicyOID is an arbitrary private-enterprise OID; RFC3161 requires
+// a policy be present but doesn't mandate a specific one for test servers.
+var testTSAPolicyOID = asn1.ObjectIdentifier{2, 16, 840, 1, 114412, 7, 1}
+
+// newTSAServer returns an httptest server that acts as a minimal RFC3161
+// TSA, always granting requests and signing with a fresh, self-signed
+// timestamping certificate.
+func newTSAServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test TSA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tsq, err := dtimestamp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ts := &dtimestamp.Timestamp{
+			HashAlgorithm: tsq.HashAlgorithm,
+			HashedMessage: tsq.HashedMessage,
+			Time:          time.Now(),
+			Nonce:         tsq.Nonce,
+			Policy:        testTSAPolicyOID,
+		}
+		resp, err := ts.CreateResponseWithOpts(cert, key, crypto.SHA256)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/timestamp-reply")
+		w.Write(resp)
+	}))
+}
+
+func TestHTTPProviderRoundTrip(t *testing.T) {
+	server := newTSAServer(t)
+	defer server.Close()
+
+	provider := NewHTTPProvider(server.URL)
+	provider.Nonce = true
+	digest := sha256.Sum256([]byte("hello"))
+
+	tst, err := provider.Timestamp(digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	require.NotEmpty(t, tst)
+
+	parsed, err := dtimestamp.Parse(tst)
+	require.NoError(t, err)
+	require.Equal(t, digest[:], parsed.HashedMessage)
+}
+
+func TestFallbackProviderUsesFirstWorking(t *testing.T) {
+	server := newTSAServer(t)
+	defer server.Close()
+
+	fb := NewFallbackProvider(brokenProvider{}, NewHTTPProvider(server.URL))
+	digest := sha256.Sum256([]byte("hello"))
+	tst, err := fb.Timestamp(digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	require.NotEmpty(t, tst)
+}
+
+func TestFallbackProviderAllFail(t *testing.T) {
+	fb := NewFallbackProvider(brokenProvider{}, brokenProvider{})
+	_, err := fb.Timestamp([]byte("digest"), crypto.SHA256)
+	require.Error(t, err)
+}
+
+type brokenProvider struct{}
+
+func (brokenProvider) Timestamp(_ []byte, _ crypto.Hash) ([]byte, error) {
+	return nil, fmt.Errorf("broken provider")
+}
+
+func TestCachingProviderDedupesWithinTTL(t *testing.T) {
+	calls := 0
+	underlying := providerFunc(func(digest []byte, hashAlg crypto.Hash) ([]byte, error) {
+		calls++
+		return []byte{byte(calls)}, nil
+	})
+	now := time.Now()
+	cache := NewCachingProvider(underlying, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	digest := []byte("digest")
+	first, err := cache.Timestamp(digest, crypto.SHA256)
+	require.NoError(t, err)
+	second, err := cache.Timestamp(digest, crypto.SHA256)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Equal(t, 1, calls)
+
+	now = now.Add(2 * time.Minute)
+	third, err := cache.Timestamp(digest, crypto.SHA256)
+	require.NoError(t, err)
+	require.NotEqual(t, first, third)
+	require.Equal(t, 2, calls)
+}
+
+type providerFunc func(digest []byte, hashAlg crypto.Hash) ([]byte, error)
+
+func (f providerFunc) Timestamp(digest []byte, hashAlg crypto.Hash) ([]byte, error) {
+	return f(digest, hashAlg)
+}
+
+func TestStaticProvider(t *testing.T) {
+	p := NewStaticProvider([]byte("fixed-tst"))
+	tst, err := p.Timestamp([]byte("anything"), crypto.SHA512)
+	require.NoError(t, err)
+	require.Equal(t, []byte("fixed-tst"), tst)
+}
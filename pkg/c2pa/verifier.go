@@ -0,0 +1,344 @@
+package c2pa
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// ValidationStatus mirrors the shape of a single entry in
+// C2PAToolOutput.ValidationStatus: a C2PA validation status code, an
+// optional spec URL, and a human-readable explanation.
+type ValidationStatus struct {
+	Code        string `json:"code"`
+	URL         string `json:"url,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// ValidationError reports that a manifest store's own ValidationStatus
+// array was non-empty, as a slice of structured codes rather than an
+// opaque formatted string. FromStream returns this instead of a plain
+// fmt.Errorf so callers can errors.As for the offending codes.
+//
+// This only covers validation failures surfaced in the manifest store
+// itself; errors returned by the underlying Rust SDK (rustC2PA.Error and
+// its variants) still carry a bare Reason string, since those types are
+// uniffi-generated and not something this package can change.
+type ValidationError struct {
+	Statuses []ValidationStatus
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Statuses) == 1 {
+		return fmt.Sprintf("validation error: %s: %s", e.Statuses[0].Code, e.Statuses[0].Explanation)
+	}
+	return fmt.Sprintf("validation error: %d issues, first is %s: %s", len(e.Statuses), e.Statuses[0].Code, e.Statuses[0].Explanation)
+}
+
+// RevocationChecker checks whether leaf was revoked by its issuer, e.g.
+// via CRL or OCSP. Implementations should return nil for "not revoked" and
+// a descriptive error otherwise; TrustPolicy treats any error as a
+// validation failure.
+type RevocationChecker interface {
+	Check(leaf, issuer *x509.Certificate) error
+}
+
+// VerificationPolicy controls how strictly VerifyProvenance treats
+// borderline findings and whether it's allowed to reach the network.
+type VerificationPolicy int
+
+const (
+	// PolicyStrict fails on any untrusted chain, disallowed algorithm, EKU
+	// mismatch, or revocation error. The default if TrustPolicy.Policy is
+	// left zero.
+	PolicyStrict VerificationPolicy = iota
+	// PolicyPermissive skips the AllowedAlgorithms/AllowedEKUs checks
+	// entirely, for callers who only care about chain-of-trust and
+	// revocation. Algorithm/EKU restrictions are opt-in strictness, not a
+	// C2PA conformance requirement, so loosening them doesn't weaken the
+	// underlying trust verification the way skipping revocation would.
+	PolicyPermissive
+	// PolicyOffline skips the Revocation check entirely, even if
+	// configured, for air-gapped verification where no OCSP/CRL fetch can
+	// succeed anyway.
+	PolicyOffline
+)
+
+// TrustPolicy configures how VerifyProvenance evaluates the certificate
+// chain backing a manifest's claim signature.
+type TrustPolicy struct {
+	// Roots is the pool of trusted issuer certificates. Required.
+	Roots *x509.CertPool
+	// Intermediates, if set, is used to build chains in addition to any
+	// intermediates embedded in the asset's own cert chain.
+	Intermediates *x509.CertPool
+	// AllowedAlgorithms restricts which signing algorithms are accepted,
+	// matched against the leaf certificate's public key. A nil or empty
+	// slice allows all algorithms GetSigningAlgorithm knows about.
+	AllowedAlgorithms []SigningAlgorithmName
+	// AllowedEKUs restricts which extended key usages the leaf
+	// certificate may carry. A nil or empty slice allows any EKU. Use the
+	// standard library's x509.ExtKeyUsage* constants.
+	AllowedEKUs []x509.ExtKeyUsage
+	// Revocation, if set, is consulted for the leaf certificate after
+	// chain validation succeeds. See OCSPChecker for a pluggable OCSP-
+	// backed implementation.
+	Revocation RevocationChecker
+	// Policy controls strictness and network access; see
+	// VerificationPolicy. Zero value is PolicyStrict.
+	Policy VerificationPolicy
+	// Clock returns the time used for certificate validity and
+	// timestamp checks. Defaults to time.Now for production use; tests
+	// should inject a fixed clock for deterministic results.
+	Clock func() time.Time
+}
+
+func (p *TrustPolicy) now() time.Time {
+	if p.Clock != nil {
+		return p.Clock()
+	}
+	return time.Now()
+}
+
+// OCSPChecker is a RevocationChecker backed by a pluggable OCSP transport
+// and response parser, so callers can point it at their own OCSP client
+// (e.g. golang.org/x/crypto/ocsp) or skip constructing one at all for
+// air-gapped verification, rather than this package taking a hard
+// dependency on an OCSP implementation.
+type OCSPChecker struct {
+	// Fetch retrieves the raw OCSP response bytes for leaf from its
+	// issuer. Required.
+	Fetch func(ctx context.Context, leaf, issuer *x509.Certificate) ([]byte, error)
+	// Parse interprets the response Fetch returned, returning a
+	// descriptive error if leaf was revoked or the response can't be
+	// trusted. Required.
+	Parse func(resp []byte, leaf, issuer *x509.Certificate) error
+	// Context is passed to Fetch. Defaults to context.Background().
+	Context context.Context
+}
+
+// Check implements RevocationChecker.
+func (c *OCSPChecker) Check(leaf, issuer *x509.Certificate) error {
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	resp, err := c.Fetch(ctx, leaf, issuer)
+	if err != nil {
+		return fmt.Errorf("verifier: fetch OCSP response: %w", err)
+	}
+	return c.Parse(resp, leaf, issuer)
+}
+
+// VerifyProvenance parses reader's provenance certificate chain (as
+// returned by Reader.GetProvenanceCertChain, PEM-encoded leaf first) and
+// validates it against policy: chain-of-trust to a configured root,
+// allowed-algorithm membership, and optional revocation status.
+//
+// It returns one ValidationStatus per problem found; a verified-trusted
+// manifest returns an empty slice and a nil error. A nil/malformed error
+// (e.g. unparseable PEM) is returned as err rather than a ValidationStatus,
+// since it indicates the input itself, not the manifest's trust, is bad.
+func VerifyProvenance(reader Reader, policy *TrustPolicy) ([]ValidationStatus, error) {
+	if policy.Roots == nil {
+		return nil, fmt.Errorf("verifier: TrustPolicy.Roots is required")
+	}
+	chain, err := parseCertChain(reader.GetProvenanceCertChain())
+	if err != nil {
+		return nil, fmt.Errorf("verifier: parse provenance cert chain: %w", err)
+	}
+	if len(chain) == 0 {
+		return []ValidationStatus{{
+			Code:        "signingCredential.missing",
+			Explanation: "no provenance certificate chain was present",
+		}}, nil
+	}
+	leaf := chain[0]
+
+	var statuses []ValidationStatus
+
+	intermediates := x509.NewCertPool()
+	if policy.Intermediates != nil {
+		intermediates = policy.Intermediates.Clone()
+	}
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// Chain validity is checked as of the manifest's own signing time, not
+	// wall-clock "now": a cert that's since expired must still verify
+	// against content it validly signed while current, which is the
+	// entire point of embedding an RFC3161 timestamp. Only fall back to
+	// policy.now() when the manifest doesn't carry a signing time at all.
+	sigTime, haveSigTime := SignatureTime(reader)
+	verificationTime := policy.now()
+	if haveSigTime {
+		verificationTime = sigTime
+	}
+
+	verifiedChains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         policy.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   verificationTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		statuses = append(statuses, ValidationStatus{
+			Code:        "signingCredential.untrusted",
+			Explanation: err.Error(),
+		})
+	}
+
+	if haveSigTime {
+		if sigTime.Before(leaf.NotBefore) || sigTime.After(leaf.NotAfter) {
+			statuses = append(statuses, ValidationStatus{
+				Code:        "signingCredential.expired",
+				Explanation: fmt.Sprintf("manifest signature time %s falls outside the signing certificate's validity window (%s to %s)", sigTime, leaf.NotBefore, leaf.NotAfter),
+			})
+		}
+	}
+
+	if policy.Policy != PolicyPermissive {
+		if alg, ok := algorithmForCertificate(leaf); !ok || !algorithmAllowed(alg, policy.AllowedAlgorithms) {
+			statuses = append(statuses, ValidationStatus{
+				Code:        "signingCredential.invalid",
+				Explanation: fmt.Sprintf("leaf certificate algorithm %q is not in the allowed-algorithm list", leaf.PublicKeyAlgorithm),
+			})
+		}
+
+		if !ekuAllowed(leaf, policy.AllowedEKUs) {
+			statuses = append(statuses, ValidationStatus{
+				Code:        "signingCredential.invalid",
+				Explanation: "leaf certificate's extended key usage is not in the allowed-EKU list",
+			})
+		}
+	}
+
+	if policy.Policy != PolicyOffline && policy.Revocation != nil && len(verifiedChains) > 0 && len(verifiedChains[0]) > 1 {
+		issuer := verifiedChains[0][1]
+		if err := policy.Revocation.Check(leaf, issuer); err != nil {
+			statuses = append(statuses, ValidationStatus{
+				Code:        "signingCredential.revoked",
+				Explanation: err.Error(),
+			})
+		}
+	}
+
+	return statuses, nil
+}
+
+// ekuAllowed reports whether cert carries at least one EKU from allowed, or
+// true unconditionally if allowed is empty.
+func ekuAllowed(cert *x509.Certificate, allowed []x509.ExtKeyUsage) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, want := range allowed {
+		for _, have := range cert.ExtKeyUsage {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseCertChain(pemChain string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(pemChain)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func algorithmForCertificate(cert *x509.Certificate) (SigningAlgorithmName, bool) {
+	switch cert.PublicKeyAlgorithm {
+	case x509.Ed25519:
+		return ED25519, true
+	case x509.ECDSA:
+		return ES256, true // exact curve (P-256/384/521/secp256k1) is checked by Verify, not here
+	case x509.RSA:
+		return PS256, true // PSS vs PKCS1v15 and hash size aren't distinguishable from the cert alone
+	default:
+		return "", false
+	}
+}
+
+func algorithmAllowed(alg SigningAlgorithmName, allowed []SigningAlgorithmName) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyFile opens fname, reads its manifest store via FromFile, and
+// evaluates its provenance against policy. It's a convenience wrapper
+// combining FromFile and VerifyProvenance for the common case where the
+// manifest's own validation status and the trust-policy status should be
+// reported together.
+func VerifyFile(fname string, policy *TrustPolicy) (Reader, []ValidationStatus, error) {
+	reader, err := FromFile(fname)
+	if err != nil {
+		if reader == nil {
+			return nil, nil, err
+		}
+		// FromStream returns a non-nil reader alongside a validation
+		// error when the store itself reports ValidationStatus entries;
+		// still run the trust policy against it.
+	}
+	statuses, verr := VerifyProvenance(reader, policy)
+	if verr != nil {
+		return reader, nil, verr
+	}
+	return reader, statuses, nil
+}
+
+// SignatureTime returns the timestamp recorded in the active manifest's
+// own signature_info, the same field c2patool reports, or false if the
+// manifest doesn't carry one. This is the Rust SDK's already-parsed and
+// validated reading of the embedded RFC3161 timestamp token; this package
+// has no ASN.1 TSTInfo parser of its own, so VerifyProvenance's time
+// check trusts that reading rather than re-deriving it from the raw
+// token.
+func SignatureTime(reader Reader) (time.Time, bool) {
+	m := reader.GetActiveManifest()
+	if m == nil {
+		return time.Time{}, false
+	}
+	bs, err := json.Marshal(m)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var decoded struct {
+		SignatureInfo struct {
+			Time time.Time `json:"time"`
+		} `json:"signature_info"`
+	}
+	if err := json.Unmarshal(bs, &decoded); err != nil || decoded.SignatureInfo.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return decoded.SignatureInfo.Time, true
+}
+
+var _ RevocationChecker = (*OCSPChecker)(nil)
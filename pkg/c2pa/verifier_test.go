@@ -0,0 +1,169 @@
+package c2pa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"git.aquareum.tv/aquareum-tv/c2pa-go/pkg/c2pa/generated/manifeststore"
+)
+
+type fakeReader struct {
+	certChain      string
+	activeManifest *manifeststore.Manifest
+}
+
+func (f *fakeReader) GetManifest(label string) *manifeststore.Manifest      { return nil }
+func (f *fakeReader) GetActiveManifest() *manifeststore.Manifest            { return f.activeManifest }
+func (f *fakeReader) GetProvenanceCertChain() string                        { return f.certChain }
+func (f *fakeReader) GetValidationStatus() []ValidationStatus               { return nil }
+func (f *fakeReader) GetAttestations() []Attestation                        { return nil }
+func (f *fakeReader) GetRemoteIngredientManifest(url string) ([]byte, bool) { return nil, false }
+
+// fakeManifestWithSignatureTime builds a manifeststore.Manifest whose JSON
+// carries a signature_info.time field, the same shape SignatureTime reads
+// back out, without depending on that generated type's exact Go field
+// names.
+func fakeManifestWithSignatureTime(t *testing.T, sigTime time.Time) *manifeststore.Manifest {
+	t.Helper()
+	bs := []byte(fmt.Sprintf(`{"signature_info":{"time":%q}}`, sigTime.Format(time.RFC3339)))
+	var m manifeststore.Manifest
+	require.NoError(t, json.Unmarshal(bs, &m))
+	return &m
+}
+
+func issueTestChain(t *testing.T) (leafPEM string, rootPool *x509.CertPool) {
+	t.Helper()
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, rootCert, &leafKey.PublicKey, rootKey)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+
+	leafPEMBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+	return string(leafPEMBytes), roots
+}
+
+func TestVerifyProvenanceTrusted(t *testing.T) {
+	leafPEM, roots := issueTestChain(t)
+	reader := &fakeReader{certChain: leafPEM}
+
+	statuses, err := VerifyProvenance(reader, &TrustPolicy{Roots: roots})
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+}
+
+func TestVerifyProvenanceUntrustedRoot(t *testing.T) {
+	leafPEM, _ := issueTestChain(t)
+	reader := &fakeReader{certChain: leafPEM}
+
+	statuses, err := VerifyProvenance(reader, &TrustPolicy{Roots: x509.NewCertPool()})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "signingCredential.untrusted", statuses[0].Code)
+}
+
+func TestVerifyProvenanceMissingChain(t *testing.T) {
+	reader := &fakeReader{certChain: ""}
+	statuses, err := VerifyProvenance(reader, &TrustPolicy{Roots: x509.NewCertPool()})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "signingCredential.missing", statuses[0].Code)
+}
+
+// TestVerifyProvenanceTrustsExpiredCertAtItsSigningTime covers the whole
+// point of embedding an RFC3161 timestamp: a cert that's since expired by
+// wall-clock "now" must still verify chain-of-trust against content it
+// validly signed while current.
+func TestVerifyProvenanceTrustsExpiredCertAtItsSigningTime(t *testing.T) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(48 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leafNotBefore := time.Now().Add(-24 * time.Hour)
+	leafNotAfter := time.Now().Add(-time.Hour) // already expired by wall clock
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test leaf"},
+		NotBefore:    leafNotBefore,
+		NotAfter:     leafNotAfter,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, rootCert, &leafKey.PublicKey, rootKey)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCert)
+	leafPEMBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+
+	sigTime := leafNotBefore.Add(time.Hour) // inside the cert's validity window
+	reader := &fakeReader{
+		certChain:      string(leafPEMBytes),
+		activeManifest: fakeManifestWithSignatureTime(t, sigTime),
+	}
+
+	statuses, err := VerifyProvenance(reader, &TrustPolicy{Roots: roots})
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+}
+
+// TestVerifyProvenanceFallsBackToClockWhenNoSignatureTime covers the other
+// branch: with no signature time recorded at all, chain validity still
+// falls back to policy.now()/Clock rather than skipping the check.
+func TestVerifyProvenanceFallsBackToClockWhenNoSignatureTime(t *testing.T) {
+	leafPEM, roots := issueTestChain(t)
+	reader := &fakeReader{certChain: leafPEM}
+
+	statuses, err := VerifyProvenance(reader, &TrustPolicy{
+		Roots: roots,
+		Clock: func() time.Time { return time.Now().Add(-48 * time.Hour) },
+	})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	require.Equal(t, "signingCredential.untrusted", statuses[0].Code)
+}